@@ -1,14 +1,102 @@
 package main
 
 import (
+	"errors"
+	"flag"
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/vinser/pacmanai/internal/agent"
 	"github.com/vinser/pacmanai/internal/app"
+	"github.com/vinser/pacmanai/internal/entity"
+	"github.com/vinser/pacmanai/internal/level"
+	"github.com/vinser/pacmanai/internal/maze"
+)
+
+// Size of generated mazes.
+const (
+	generatedWidth  = 27
+	generatedHeight = 23
 )
 
 func main() {
-	p := tea.NewProgram(app.NewModel())
+	if len(os.Args) > 1 && os.Args[1] == "maze" {
+		os.Exit(runMaze(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		os.Exit(runServe(os.Args[2:]))
+	}
+
+	// Any int64 is a valid seed, so an unset flag is told apart by seedSet
+	// rather than by a reserved value.
+	var seed int64
+	seedSet := false
+	flag.Func("seed", "random seed for the game (default: picked from the clock)", func(s string) error {
+		v, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return errors.New("seed must be an integer")
+		}
+		seed, seedSet = v, true
+		return nil
+	})
+	ghostAI := flag.String("ghost-ai", "", "ghost brain for all ghosts, or ghost=brain pairs separated by commas (brains: "+strings.Join(entity.BrainNames(), ", ")+")")
+	agentName := flag.String("agent", "", "let a bot play Pac-Man (agents: "+strings.Join(agent.Names(), ", ")+")")
+	mazeFile := flag.String("maze", "", "play on the maze in this text file instead of the default")
+	levelsFile := flag.String("levels", "", "JSON level table to use instead of the arcade progression")
+	extraLife := flag.Int("extra-life", 10000, "score that earns an extra life (0 for 10000, negative disables)")
+	extraLifeEvery := flag.Int("extra-life-every", 0, "repeat the extra life every this many points (0 awards it once)")
+	generate := flag.Bool("generate", false, "play every level on a freshly generated maze")
+	flag.Parse()
+
+	if !seedSet {
+		seed = time.Now().UnixNano()
+	}
+	brains, err := entity.ParseBrains(*ghostAI)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(2)
+	}
+
+	opts := app.Options{
+		Seed:           seed,
+		Brains:         brains,
+		ExtraLife:      *extraLife,
+		ExtraLifeEvery: *extraLifeEvery,
+	}
+	if *levelsFile != "" {
+		if opts.Levels, err = level.LoadTable(*levelsFile); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(2)
+		}
+	}
+	if *mazeFile != "" {
+		m, err := loadMaze(*mazeFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(2)
+		}
+		opts.Mazes = level.FixedMaze(m)
+	}
+	if *generate {
+		src, err := level.GeneratedMazes(seed, generatedWidth, generatedHeight, maze.DefaultGenOptions(generatedHeight))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(2)
+		}
+		opts.Mazes = src
+	}
+	if *agentName != "" {
+		if opts.Agent, err = agent.New(*agentName); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(2)
+		}
+	}
+
+	p := tea.NewProgram(app.NewModel(opts))
 	if _, err := p.Run(); err != nil {
 		println("Error:", err)
 		os.Exit(1)
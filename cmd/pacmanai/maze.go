@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/vinser/pacmanai/internal/maze"
+)
+
+// runMaze implements the "maze" subcommand and returns the exit code.
+func runMaze(args []string) int {
+	if len(args) != 2 || args[0] != "validate" {
+		fmt.Fprintln(os.Stderr, "usage: pacmanai maze validate <file>")
+		return 2
+	}
+	m, err := maze.Load(args[1])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	problems := maze.Validate(m)
+	for _, p := range problems {
+		fmt.Println(p)
+	}
+	if maze.HasErrors(problems) {
+		return 1
+	}
+	if len(problems) == 0 {
+		fmt.Println("ok")
+	}
+	return 0
+}
+
+// loadMaze reads a maze file and rejects mazes that cannot be played,
+// listing their problems in the error.
+func loadMaze(path string) (*maze.Maze, error) {
+	m, err := maze.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	if problems := maze.Validate(m); maze.HasErrors(problems) {
+		var sb strings.Builder
+		for _, p := range problems {
+			sb.WriteString("\n  ")
+			sb.WriteString(p.String())
+		}
+		return nil, fmt.Errorf("%s: unplayable maze:%s", path, sb.String())
+	}
+	return m, nil
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/vinser/pacmanai/internal/entity"
+	"github.com/vinser/pacmanai/internal/env"
+	"github.com/vinser/pacmanai/internal/level"
+	"github.com/vinser/pacmanai/internal/maze"
+	"github.com/vinser/pacmanai/internal/observe"
+)
+
+// runServe implements the "serve" subcommand and returns the exit code.
+func runServe(args []string) int {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: pacmanai serve [flags]")
+		fmt.Fprintln(fs.Output(), "Serves the game to training agents as JSON lines; see package env for the protocol.")
+		fs.PrintDefaults()
+	}
+	addr := fs.String("addr", "127.0.0.1:5555", "TCP host:port, or unix:path for a Unix socket")
+	ghostAI := fs.String("ghost-ai", "", "ghost brain for all ghosts, or ghost=brain pairs separated by commas (brains: "+strings.Join(entity.BrainNames(), ", ")+")")
+	mazeFile := fs.String("maze", "", "play on the maze in this text file instead of the default")
+	levelsFile := fs.String("levels", "", "JSON level table to use instead of the arcade progression")
+	generate := fs.Bool("generate", false, "play every level on a maze generated from the episode seed")
+	rewardFile := fs.String("reward", "", "JSON reward config with event weights, penalties and shaping (default: the points scored)")
+	maxSteps := fs.Int("max-steps", 0, "end episodes after this many steps (0 means no limit)")
+	encoding := fs.String("encoding", "", "add a tensor of the board to every observation: "+strings.Join(observe.EncodingNames, ", "))
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg := env.Config{GhostAI: *ghostAI, MaxSteps: *maxSteps}
+	if _, err := entity.ParseBrains(*ghostAI); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 2
+	}
+	if *levelsFile != "" {
+		t, err := level.LoadTable(*levelsFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 2
+		}
+		cfg.Levels = t
+	}
+	if *encoding != "" {
+		enc, err := observe.ParseEncoding(*encoding)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 2
+		}
+		cfg.Encoding = &enc
+	}
+	if *rewardFile != "" {
+		r, err := entity.LoadRewardConfig(*rewardFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 2
+		}
+		cfg.Reward = &r
+	}
+	if *mazeFile != "" {
+		m, err := loadMaze(*mazeFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 2
+		}
+		src := level.FixedMaze(m)
+		cfg.Mazes = func(int64) (level.MazeSource, error) { return src, nil }
+	}
+	if *generate {
+		cfg.Mazes = func(seed int64) (level.MazeSource, error) {
+			return level.GeneratedMazes(seed, generatedWidth, generatedHeight, maze.DefaultGenOptions(generatedHeight))
+		}
+	}
+
+	l, err := env.Listen(*addr)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	defer l.Close()
+	// Closing the listener on a signal also removes a Unix socket file.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		l.Close()
+	}()
+	fmt.Fprintln(os.Stderr, "serving on", l.Addr())
+	logf := func(format string, args ...any) {
+		fmt.Fprintf(os.Stderr, format+"\n", args...)
+	}
+	if err := env.ServeListener(l, cfg, logf); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,81 @@
+// Package agent provides autonomous Pac-Man players.
+package agent
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/vinser/pacmanai/internal/entity"
+	"github.com/vinser/pacmanai/internal/maze"
+	"github.com/vinser/pacmanai/internal/pathfind"
+	"github.com/vinser/pacmanai/internal/sim"
+)
+
+// Agent chooses Pac-Man's direction from what it observes each tick.
+type Agent interface {
+	Act(obs sim.Observation) entity.Direction
+}
+
+// Factory creates a fresh agent.
+type Factory func() Agent
+
+var agents = map[string]Factory{
+	"greedy": func() Agent { return Greedy{} },
+	"safe":   func() Agent { return Safe{} },
+}
+
+// Register makes an agent available under name.
+// It panics if the name is already taken.
+func Register(name string, f Factory) {
+	if _, dup := agents[name]; dup {
+		panic("agent: registered twice: " + name)
+	}
+	agents[name] = f
+}
+
+// New creates the registered agent with the given name.
+func New(name string) (Agent, error) {
+	f, ok := agents[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown agent %q (available: %s)", name, strings.Join(Names(), ", "))
+	}
+	return f(), nil
+}
+
+// Names returns the names of all registered agents in sorted order.
+func Names() []string {
+	names := make([]string, 0, len(agents))
+	for name := range agents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var directions = []entity.Direction{entity.Up, entity.Left, entity.Down, entity.Right}
+
+// isFood reports whether the tile at p holds a dot or a power pellet.
+func isFood(m *maze.Maze, p entity.Position) bool {
+	tile, err := m.TileAt(p.X, p.Y)
+	return err == nil && (tile == maze.Dot || tile == maze.PowerPellet)
+}
+
+// search returns the first direction of a shortest path from start to the
+// nearest other tile satisfying goal, never entering tiles in blocked.
+func search(m *maze.Maze, start entity.Position, blocked map[maze.Point]bool, goal func(entity.Position) bool) (entity.Direction, bool) {
+	from := maze.Point{X: start.X, Y: start.Y}
+	path, ok := pathfind.NewGraph(m, false).Nearest(from, func(p maze.Point) bool {
+		return p != from && goal(entity.Position{X: p.X, Y: p.Y})
+	}, blocked)
+	if !ok {
+		return entity.Up, false
+	}
+	first := entity.Position{X: path[0].X, Y: path[0].Y}
+	for _, d := range directions {
+		if next, _ := entity.NextTile(start, d, m); next == first {
+			return d, true
+		}
+	}
+	return entity.Up, false
+}
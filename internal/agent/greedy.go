@@ -0,0 +1,18 @@
+package agent
+
+import (
+	"github.com/vinser/pacmanai/internal/entity"
+	"github.com/vinser/pacmanai/internal/sim"
+)
+
+// Greedy walks the shortest path to the nearest dot and ignores ghosts.
+type Greedy struct{}
+
+// Act implements Agent.
+func (Greedy) Act(obs sim.Observation) entity.Direction {
+	food := func(p entity.Position) bool { return isFood(obs.Maze, p) }
+	if d, ok := search(obs.Maze, obs.Pacman, nil, food); ok {
+		return d
+	}
+	return obs.PacmanDir
+}
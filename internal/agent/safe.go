@@ -0,0 +1,70 @@
+package agent
+
+import (
+	"github.com/vinser/pacmanai/internal/entity"
+	"github.com/vinser/pacmanai/internal/maze"
+	"github.com/vinser/pacmanai/internal/sim"
+)
+
+// Safe walks the shortest path to the nearest dot that keeps clear of
+// dangerous ghosts and the tiles next to them. When no such path exists it
+// runs to the neighbor farthest from the closest dangerous ghost.
+type Safe struct{}
+
+// Act implements Agent.
+func (Safe) Act(obs sim.Observation) entity.Direction {
+	food := func(p entity.Position) bool { return isFood(obs.Maze, p) }
+	if d, ok := search(obs.Maze, obs.Pacman, dangerZone(obs), food); ok {
+		return d
+	}
+	return flee(obs)
+}
+
+// dangerZone returns the tiles occupied by or adjacent to roaming ghosts.
+func dangerZone(obs sim.Observation) map[maze.Point]bool {
+	zone := map[maze.Point]bool{}
+	for _, g := range obs.Ghosts {
+		if g.State != entity.Chase && g.State != entity.Scatter {
+			continue
+		}
+		zone[maze.Point{X: g.Pos.X, Y: g.Pos.Y}] = true
+		for _, d := range directions {
+			if next, ok := entity.NextTile(g.Pos, d, obs.Maze); ok {
+				zone[maze.Point{X: next.X, Y: next.Y}] = true
+			}
+		}
+	}
+	return zone
+}
+
+// flee picks the open direction that maximizes the Manhattan distance to
+// the nearest roaming ghost.
+func flee(obs sim.Observation) entity.Direction {
+	best, bestDist := obs.PacmanDir, -1
+	for _, d := range directions {
+		next, ok := entity.NextTile(obs.Pacman, d, obs.Maze)
+		if !ok {
+			continue
+		}
+		nearest := 1 << 30
+		for _, g := range obs.Ghosts {
+			if g.State != entity.Chase && g.State != entity.Scatter {
+				continue
+			}
+			if dist := abs(next.X-g.Pos.X) + abs(next.Y-g.Pos.Y); dist < nearest {
+				nearest = dist
+			}
+		}
+		if nearest > bestDist {
+			best, bestDist = d, nearest
+		}
+	}
+	return best
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,48 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/vinser/pacmanai/internal/sim"
+	"github.com/vinser/pacmanai/internal/state"
+)
+
+func TestQuitSavesSeed(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	// Zero is a seed like any other.
+	for _, seed := range []int64{42, 0} {
+		var m tea.Model = NewModel(Options{Seed: seed})
+		for i := 0; i < 10; i++ {
+			m, _ = m.Update(tickMsg{})
+		}
+		if !strings.Contains(m.View(), fmt.Sprintf("Seed: %d", seed)) {
+			t.Errorf("the HUD does not show seed %d:\n%s", seed, m.View())
+		}
+		_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+		if cmd == nil {
+			t.Fatal("q did not quit")
+		}
+		if got := state.Load().LastSeed; got != seed {
+			t.Errorf("saved seed %d after quitting, want %d", got, seed)
+		}
+	}
+}
+
+func TestTimersFollowTicks(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	m := NewModel(Options{Seed: 1})
+	start := m.game.Clock().Now()
+	// However late the tick messages arrive, each one is one tick of game
+	// time, as in the headless game.
+	var tm tea.Model = m
+	for i := 0; i < 40; i++ {
+		tm, _ = tm.Update(tickMsg{})
+	}
+	if got := tm.(Model).game.Clock().Now().Sub(start); got != 40*sim.TickDuration {
+		t.Errorf("40 ticks advanced the game clock by %v, want %v", got, 40*sim.TickDuration)
+	}
+}
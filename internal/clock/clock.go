@@ -0,0 +1,79 @@
+// Package clock provides interchangeable time sources so that timed game
+// behavior can run on the wall clock, under manual control, or in lockstep
+// with simulation ticks.
+package clock
+
+import "time"
+
+// Clock reports the current time.
+type Clock interface {
+	Now() time.Time
+}
+
+// Ticker is a clock that is advanced explicitly once per simulation tick.
+type Ticker interface {
+	Clock
+	Tick()
+}
+
+// epoch is the start time of clocks that do not follow the wall clock.
+var epoch = time.Unix(0, 0).UTC()
+
+// Real reads the wall clock.
+type Real struct{}
+
+// Now returns the current wall-clock time.
+func (Real) Now() time.Time {
+	return time.Now()
+}
+
+// Manual is a clock that only moves when told to.
+type Manual struct {
+	now time.Time
+}
+
+// NewManual returns a manual clock stopped at the given time.
+func NewManual(start time.Time) *Manual {
+	return &Manual{now: start}
+}
+
+// Now returns the clock's current time.
+func (c *Manual) Now() time.Time {
+	return c.now
+}
+
+// Set moves the clock to t.
+func (c *Manual) Set(t time.Time) {
+	c.now = t
+}
+
+// Advance moves the clock forward by d.
+func (c *Manual) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+// Ticks is a clock that advances by a fixed step on every Tick.
+type Ticks struct {
+	step  time.Duration
+	count int64
+}
+
+// NewTicks returns a tick-counted clock advancing step per tick.
+func NewTicks(step time.Duration) *Ticks {
+	return &Ticks{step: step}
+}
+
+// Now returns the time after the ticks counted so far.
+func (c *Ticks) Now() time.Time {
+	return epoch.Add(time.Duration(c.count) * c.step)
+}
+
+// Tick advances the clock by one step.
+func (c *Ticks) Tick() {
+	c.count++
+}
+
+// Count returns the number of ticks counted so far.
+func (c *Ticks) Count() int64 {
+	return c.count
+}
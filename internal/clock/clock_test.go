@@ -0,0 +1,40 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestManual(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewManual(start)
+	if got := c.Now(); !got.Equal(start) {
+		t.Fatalf("Now() = %v, want %v", got, start)
+	}
+	c.Advance(1500 * time.Millisecond)
+	c.Advance(500 * time.Millisecond)
+	if got := c.Now().Sub(start); got != 2*time.Second {
+		t.Errorf("after advancing 2s the clock moved %v", got)
+	}
+	c.Set(start)
+	if got := c.Now(); !got.Equal(start) {
+		t.Errorf("after Set, Now() = %v, want %v", got, start)
+	}
+}
+
+func TestTicks(t *testing.T) {
+	c := NewTicks(50 * time.Millisecond)
+	start := c.Now()
+	for i := 0; i < 20; i++ {
+		c.Tick()
+	}
+	if c.Count() != 20 {
+		t.Errorf("Count() = %d, want 20", c.Count())
+	}
+	if got := c.Now().Sub(start); got != time.Second {
+		t.Errorf("20 ticks of 50ms moved the clock %v, want 1s", got)
+	}
+	if !NewTicks(time.Second).Now().Equal(start) {
+		t.Errorf("tick clocks do not start at the same time")
+	}
+}
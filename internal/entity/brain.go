@@ -0,0 +1,193 @@
+package entity
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/vinser/pacmanai/internal/maze"
+	"github.com/vinser/pacmanai/internal/pathfind"
+)
+
+// GhostBrain decides where a roaming (chasing or scattering) ghost goes
+// next. Frightened and eaten ghosts follow the game rules instead.
+type GhostBrain interface {
+	// Decide returns the direction self should take from its current tile.
+	Decide(self GhostView, w World) Direction
+}
+
+// BrainFactory creates a fresh brain for a single ghost.
+type BrainFactory func() GhostBrain
+
+var brains = map[string]BrainFactory{}
+
+func init() {
+	RegisterBrain("arcade", func() GhostBrain { return arcadeBrain{} })
+	RegisterBrain("random", func() GhostBrain { return randomBrain{} })
+	RegisterBrain("greedy", func() GhostBrain { return greedyBrain{} })
+	RegisterBrain("bfs", func() GhostBrain { return bfsBrain{} })
+}
+
+// RegisterBrain makes a ghost brain available under name.
+// It panics if the name is already taken.
+func RegisterBrain(name string, f BrainFactory) {
+	if _, dup := brains[name]; dup {
+		panic("entity: ghost brain registered twice: " + name)
+	}
+	brains[name] = f
+}
+
+// NewBrain creates the registered brain with the given name.
+func NewBrain(name string) (GhostBrain, error) {
+	f, ok := brains[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown ghost brain %q (available: %s)", name, strings.Join(BrainNames(), ", "))
+	}
+	return f(), nil
+}
+
+// BrainNames returns the names of all registered brains in sorted order.
+func BrainNames() []string {
+	names := make([]string, 0, len(brains))
+	for name := range brains {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseBrains builds per-ghost brains from a spec that is either a single
+// brain name used for every ghost ("bfs") or a comma-separated list of
+// ghost=brain pairs ("blinky=bfs,clyde=random"). Ghosts not mentioned get
+// no entry and fall back to the default brain.
+func ParseBrains(spec string) (map[GhostType]GhostBrain, error) {
+	out := map[GhostType]GhostBrain{}
+	if spec == "" {
+		return out, nil
+	}
+	if !strings.Contains(spec, "=") {
+		for _, t := range []GhostType{Blinky, Inky, Pinky, Clyde} {
+			b, err := NewBrain(spec)
+			if err != nil {
+				return nil, err
+			}
+			out[t] = b
+		}
+		return out, nil
+	}
+	for _, pair := range strings.Split(spec, ",") {
+		ghost, name, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid ghost brain %q: want ghost=brain", pair)
+		}
+		t, err := ParseGhostType(ghost)
+		if err != nil {
+			return nil, err
+		}
+		b, err := NewBrain(name)
+		if err != nil {
+			return nil, err
+		}
+		out[t] = b
+	}
+	return out, nil
+}
+
+// brainTarget returns the tile a ghost heads for in its current mode.
+func brainTarget(self GhostView, w World) Position {
+	if self.State == Scatter {
+		return ScatterCorner(self.Type, w.Maze)
+	}
+	return TargeterFor(self.Type).Target(self, w)
+}
+
+// arcadeBrain follows the arcade personalities: each ghost type has its own
+// chase target and Euclidean steering with no reversals.
+type arcadeBrain struct{}
+
+func (arcadeBrain) Decide(self GhostView, w World) Direction {
+	return steer(self, brainTarget(self, w), w.Maze, distSq)
+}
+
+// randomBrain wanders, picking a random open direction at every tile.
+type randomBrain struct{}
+
+func (randomBrain) Decide(self GhostView, w World) Direction {
+	exits := exitsFrom(self.Pos, self.Dir, w.Maze)
+	if len(exits) == 0 {
+		return self.Dir
+	}
+	return exits[w.Rand.Intn(len(exits))]
+}
+
+// greedyBrain heads for Pac-Man (or its corner when scattering) by
+// minimizing Manhattan distance one tile at a time.
+type greedyBrain struct{}
+
+func (greedyBrain) Decide(self GhostView, w World) Direction {
+	target := w.Pacman
+	if self.State == Scatter {
+		target = ScatterCorner(self.Type, w.Maze)
+	}
+	return steer(self, target, w.Maze, manhattan)
+}
+
+// bfsBrain follows a shortest path to Pac-Man. While scattering, or when
+// Pac-Man cannot be reached, it falls back to arcade steering.
+type bfsBrain struct{}
+
+func (bfsBrain) Decide(self GhostView, w World) Direction {
+	if self.State != Scatter {
+		if d, ok := firstStep(self.Pos, w.Pacman, w.Maze); ok {
+			return d
+		}
+	}
+	return steer(self, brainTarget(self, w), w.Maze, distSq)
+}
+
+// steer picks the open non-reversing direction whose next tile is closest
+// to target under metric. Ties are broken in the arcade order: up, left,
+// down, right. Reversing is allowed only at dead ends.
+func steer(self GhostView, target Position, m *maze.Maze, metric func(a, b Position) int) Direction {
+	best, found := self.Dir, false
+	shortest := 0
+	for _, d := range exitsFrom(self.Pos, self.Dir, m) {
+		next, _ := NextTile(self.Pos, d, m)
+		if dist := metric(next, target); !found || dist < shortest {
+			best, shortest, found = d, dist, true
+		}
+	}
+	return best
+}
+
+// exitsFrom lists the open directions from pos in arcade priority order,
+// excluding the reverse of dir unless it is the only way out.
+func exitsFrom(pos Position, dir Direction, m *maze.Maze) []Direction {
+	var dirs []Direction
+	opp := oppositeDirection(dir)
+	for _, d := range []Direction{Up, Left, Down, Right} {
+		if d != opp && canMoveTo(pos, d, m) {
+			dirs = append(dirs, d)
+		}
+	}
+	if len(dirs) == 0 && canMoveTo(pos, opp, m) {
+		dirs = append(dirs, opp)
+	}
+	return dirs
+}
+
+// firstStep returns the first direction of a shortest path from start to
+// goal through open tiles and tunnels.
+func firstStep(start, goal Position, m *maze.Maze) (Direction, bool) {
+	graph := pathfind.NewGraph(m, false)
+	next, ok := graph.BFS(toPoint(goal)).Next(toPoint(start))
+	if !ok {
+		return Up, false
+	}
+	return directionTo(start, toPosition(next), graph), true
+}
+
+// manhattan returns the Manhattan distance between two positions.
+func manhattan(a, b Position) int {
+	return abs(a.X-b.X) + abs(a.Y-b.Y)
+}
@@ -0,0 +1,91 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FruitKind identifies a bonus fruit.
+type FruitKind int
+
+const (
+	Cherry FruitKind = iota
+	Strawberry
+	Orange
+	Apple
+	Melon
+	Galaxian
+	Bell
+	Key
+)
+
+var fruitNames = []string{"cherry", "strawberry", "orange", "apple", "melon", "galaxian", "bell", "key"}
+
+// String returns the lowercase name of the fruit.
+func (k FruitKind) String() string {
+	if k >= 0 && int(k) < len(fruitNames) {
+		return fruitNames[k]
+	}
+	return "fruit"
+}
+
+// Rune returns the character used to render the fruit.
+func (k FruitKind) Rune() rune {
+	switch k {
+	case Cherry:
+		return '%'
+	case Strawberry:
+		return '&'
+	case Orange:
+		return '@'
+	case Apple:
+		return '$'
+	case Melon:
+		return '='
+	case Galaxian:
+		return '^'
+	case Bell:
+		return '!'
+	case Key:
+		return '~'
+	default:
+		return '*'
+	}
+}
+
+// ParseFruit returns the fruit kind with the given name.
+func ParseFruit(name string) (FruitKind, error) {
+	for i, n := range fruitNames {
+		if strings.EqualFold(name, n) {
+			return FruitKind(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown fruit %q", name)
+}
+
+// Fruit is a bonus item waiting to be eaten.
+type Fruit struct {
+	kind     FruitKind
+	points   int
+	position Position
+}
+
+// NewFruit creates a fruit of the given kind and value at pos.
+func NewFruit(kind FruitKind, points int, pos Position) *Fruit {
+	return &Fruit{kind: kind, points: points, position: pos}
+}
+
+// Kind returns the type of fruit.
+func (f *Fruit) Kind() FruitKind {
+	return f.kind
+}
+
+// Points returns the score for eating the fruit.
+func (f *Fruit) Points() int {
+	return f.points
+}
+
+// Pos returns the position of the fruit.
+func (f *Fruit) Pos() Position {
+	return f.position
+}
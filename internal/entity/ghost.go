@@ -1,9 +1,13 @@
 package entity
 
 import (
+	"fmt"
 	"math/rand"
+	"strings"
+	"time"
 
 	"github.com/vinser/pacmanai/internal/maze"
+	"github.com/vinser/pacmanai/internal/pathfind"
 )
 
 // GhostState defines the current behavior mode of a ghost.
@@ -33,17 +37,53 @@ type Ghost struct {
 	state     GhostState
 	ghostType GhostType
 	home      Position
+	house     HouseState
+	motion    Motion
+	speed     int
+	elroy     int
+	brain     GhostBrain
 }
 
-// NewGhost creates a ghost with specified type and home position.
-func NewGhost(t GhostType, home Position) *Ghost {
+// NewGhost creates a ghost with specified type, home position and brain.
+// A nil brain selects the arcade behavior.
+func NewGhost(t GhostType, home Position, brain GhostBrain) *Ghost {
+	if brain == nil {
+		brain = arcadeBrain{}
+	}
 	return &Ghost{
 		home:      home,
 		position:  home,
 		direction: Left,
 		state:     Chase,
 		ghostType: t,
+		brain:     brain,
+	}
+}
+
+// String returns the lowercase name of the ghost type.
+func (t GhostType) String() string {
+	switch t {
+	case Blinky:
+		return "blinky"
+	case Inky:
+		return "inky"
+	case Pinky:
+		return "pinky"
+	case Clyde:
+		return "clyde"
+	default:
+		return "ghost"
+	}
+}
+
+// ParseGhostType returns the ghost type with the given name.
+func ParseGhostType(name string) (GhostType, error) {
+	for _, t := range []GhostType{Blinky, Inky, Pinky, Clyde} {
+		if strings.EqualFold(name, t.String()) {
+			return t, nil
+		}
 	}
+	return 0, fmt.Errorf("unknown ghost %q", name)
 }
 
 // Rune returns the character used to render the ghost.
@@ -62,14 +102,42 @@ func (g *Ghost) Rune() rune {
 	}
 }
 
+// Type returns the ghost's identity.
+func (g *Ghost) Type() GhostType {
+	return g.ghostType
+}
+
+// Dir returns the ghost's current direction.
+func (g *Ghost) Dir() Direction {
+	return g.direction
+}
+
+// View returns a read-only snapshot of the ghost.
+func (g *Ghost) View() GhostView {
+	return GhostView{
+		Type:  g.ghostType,
+		Pos:   g.position,
+		Dir:   g.direction,
+		State: g.state,
+		Home:  g.home,
+	}
+}
+
 // Pos returns the current position of the ghost.
 func (g *Ghost) Pos() Position {
 	return g.position
 }
 
-// SetPos sets the ghost's position directly.
+// SetPos sets the ghost's position directly, dropping sub-tile progress.
 func (g *Ghost) SetPos(pos Position) {
 	g.position = pos
+	g.motion.Stop()
+}
+
+// Advance accumulates dt of travel at speed percent and returns the number
+// of tiles the ghost moves this tick.
+func (g *Ghost) Advance(speed int, dt time.Duration) int {
+	return g.motion.Advance(speed, dt)
 }
 
 // State returns the current state of the ghost.
@@ -82,11 +150,47 @@ func (g *Ghost) SetState(state GhostState) {
 	g.state = state
 }
 
+// RoamState returns the state the ghost roams in while the others follow
+// mode. Cruise Elroy keeps chasing through scatter waves.
+func (g *Ghost) RoamState(mode GhostState) GhostState {
+	if g.elroy > 0 {
+		return Chase
+	}
+	return mode
+}
+
+// Speed returns the ghost's own roaming speed percent, or 0 if it moves at
+// the level's ghost speed.
+func (g *Ghost) Speed() int {
+	return g.speed
+}
+
+// SetSpeed overrides the ghost's roaming speed percent. Zero restores the
+// level's ghost speed.
+func (g *Ghost) SetSpeed(speed int) {
+	g.speed = speed
+}
+
+// Elroy returns the ghost's Cruise Elroy stage, 0 when it is not active.
+func (g *Ghost) Elroy() int {
+	return g.elroy
+}
+
+// SetElroy sets the ghost's Cruise Elroy stage.
+func (g *Ghost) SetElroy(stage int) {
+	g.elroy = stage
+}
+
 // Home returns the ghost's home position.
 func (g *Ghost) Home() Position {
 	return g.home
 }
 
+// SetHome moves the ghost's home position.
+func (g *Ghost) SetHome(home Position) {
+	g.home = home
+}
+
 // Move moves the ghost in its current direction.
 // NextPos returns the position the ghost would move to.
 func (g *Ghost) NextPos() Position {
@@ -104,74 +208,65 @@ func (g *Ghost) NextPos() Position {
 	return pos
 }
 
-// MoveGhosts moves each ghost according to its state.
-func MoveGhosts(ghosts []*Ghost, m *maze.Maze, powerMode bool) {
-	for _, g := range ghosts {
-		switch g.State() {
-		case Frightened:
-			g.MoveRandom(m)
-		case Eaten:
-			if g.Pos() == g.Home() {
-				if !powerMode {
-					g.SetState(Chase)
-				}
-			} else {
-				g.MoveToHome(m)
-			}
-		default:
-			g.MoveRandom(m)
-		}
+// Step moves the ghost one tile according to its state. Roaming ghosts are
+// steered by their brains. All random choices are drawn from w.Rand so
+// that games can be replayed.
+func (g *Ghost) Step(w World) {
+	if g.moveInHouse(w) {
+		return
+	}
+	switch g.State() {
+	case Frightened:
+		g.MoveRandom(w.Maze, w.Rand)
+	case Eaten:
+		g.returnHome(w)
+	default:
+		g.direction = g.brain.Decide(g.View(), w)
+		g.Move(w.Maze)
 	}
 }
 
 // Move tries to move the ghost forward if not hitting a wall.
 func (g *Ghost) Move(m *maze.Maze) {
-	next := g.NextPos()
+	if next, ok := NextTile(g.position, g.direction, m); ok {
+		g.position = next
+	}
+}
 
-	if m.IsTunnelRow(next.Y) {
-		if next.X < 0 {
-			next.X = m.Width() - 1
-		} else if next.X >= m.Width() {
-			next.X = 0
+// returnHome moves eaten eyes back towards the ghost house, or to the
+// ghost's home tile in mazes without a house, and revives them there.
+func (g *Ghost) returnHome(w World) {
+	if exit, ok := w.Maze.HouseExit(); ok {
+		if g.position != toPosition(exit) {
+			g.moveTowards(toPosition(exit), w.Maze)
+		}
+		if g.position == toPosition(exit) {
+			g.house = EnteringHouse
 		}
+		return
 	}
-
-	tile, err := m.TileAt(next.X, next.Y)
-	if err == nil && tile != maze.Wall {
-		g.position = next
+	if g.position != g.home {
+		g.MoveToHome(w.Maze)
+	}
+	if g.position == g.home {
+		g.state = g.RoamState(w.Mode)
 	}
 }
 
-// MoveToHome moves the ghost one step closer to its home position.
+// MoveToHome moves the ghost one step along a shortest path to its home
+// position.
 func (g *Ghost) MoveToHome(m *maze.Maze) {
-	bestDir := g.direction
-	shortest := 1 << 30 // large number
-
-	for _, d := range []Direction{Up, Down, Left, Right} {
-		next := g.position.moveIn(d)
-
-		if m.IsTunnelRow(next.Y) {
-			if next.X < 0 {
-				next.X = m.Width() - 1
-			} else if next.X >= m.Width() {
-				next.X = 0
-			}
-		}
-
-		tile, err := m.TileAt(next.X, next.Y)
-		if err != nil || tile == maze.Wall {
-			continue
-		}
+	g.moveTowards(g.home, m)
+}
 
-		dist := abs(next.X-g.home.X) + abs(next.Y-g.home.Y)
-		if dist < shortest {
-			shortest = dist
-			bestDir = d
-		}
+// moveTowards moves the ghost one step along a shortest path to target,
+// using the maze's cached distance map.
+func (g *Ghost) moveTowards(target Position, m *maze.Maze) {
+	graph := pathfind.NewGraph(m, false)
+	if next, ok := graph.Distances(toPoint(target)).Next(toPoint(g.position)); ok {
+		g.direction = directionTo(g.position, toPosition(next), graph)
+		g.position = toPosition(next)
 	}
-
-	g.direction = bestDir
-	g.Move(m)
 }
 
 func abs(x int) int {
@@ -181,12 +276,18 @@ func abs(x int) int {
 	return x
 }
 
+// Reverse turns the ghost around.
+func (g *Ghost) Reverse() {
+	g.direction = oppositeDirection(g.direction)
+}
+
 // SetDirection sets the ghost's movement direction.
 func (g *Ghost) SetDirection(dir Direction) {
 	g.direction = dir
 }
 
-func (g *Ghost) MoveRandom(m *maze.Maze) {
+// MoveRandom moves the ghost in a random open direction drawn from rng.
+func (g *Ghost) MoveRandom(m *maze.Maze, rng *rand.Rand) {
 	// Направления, кроме обратного
 	possible := g.validDirectionsExcludingOpposite(m)
 
@@ -199,7 +300,7 @@ func (g *Ghost) MoveRandom(m *maze.Maze) {
 		return // полностью заблокирован
 	}
 
-	g.direction = possible[rand.Intn(len(possible))]
+	g.direction = possible[rng.Intn(len(possible))]
 	g.Move(m)
 }
 
@@ -237,18 +338,7 @@ func (g *Ghost) validDirections(m *maze.Maze) []Direction {
 		if d == opp {
 			continue // не разворачиваемся
 		}
-		pos := g.position.moveIn(d)
-
-		if m.IsTunnelRow(pos.Y) {
-			if pos.X < 0 {
-				pos.X = m.Width() - 1
-			} else if pos.X >= m.Width() {
-				pos.X = 0
-			}
-		}
-
-		tile, err := m.TileAt(pos.X, pos.Y)
-		if err == nil && tile != maze.Wall {
+		if canMoveTo(g.position, d, m) {
 			dirs = append(dirs, d)
 		}
 	}
@@ -256,18 +346,26 @@ func (g *Ghost) validDirections(m *maze.Maze) []Direction {
 }
 
 func canMoveTo(pos Position, d Direction, m *maze.Maze) bool {
-	p := pos.moveIn(d)
+	_, ok := NextTile(pos, d, m)
+	return ok
+}
+
+// NextTile returns the tile reached by moving from pos in direction d,
+// wrapping through tunnels, and whether that tile is open.
+func NextTile(pos Position, d Direction, m *maze.Maze) (Position, bool) {
+	p, ok := pathfind.NewGraph(m, false).Step(toPoint(pos), d.offset())
+	return toPosition(p), ok
+}
 
-	if m.IsTunnelRow(p.Y) {
-		if p.X < 0 {
-			p.X = m.Width() - 1
-		} else if p.X >= m.Width() {
-			p.X = 0
+// directionTo returns the direction of the step from pos to the adjacent
+// tile next.
+func directionTo(pos, next Position, graph pathfind.Graph) Direction {
+	for _, d := range []Direction{Up, Left, Down, Right} {
+		if p, _ := graph.Step(toPoint(pos), d.offset()); toPosition(p) == next {
+			return d
 		}
 	}
-
-	tile, err := m.TileAt(p.X, p.Y)
-	return err == nil && tile != maze.Wall
+	return Up
 }
 
 func (p Position) moveIn(d Direction) Position {
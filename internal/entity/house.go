@@ -0,0 +1,114 @@
+package entity
+
+import (
+	"github.com/vinser/pacmanai/internal/maze"
+	"github.com/vinser/pacmanai/internal/pathfind"
+)
+
+// HouseState tells where a ghost is relative to the ghost house.
+type HouseState int
+
+const (
+	OutsideHouse HouseState = iota
+	// InHouse ghosts bob up and down until they are released.
+	InHouse
+	// LeavingHouse ghosts head through the door to the tile outside it.
+	LeavingHouse
+	// EnteringHouse ghosts are eaten eyes going back in to be revived.
+	EnteringHouse
+)
+
+// House returns where the ghost is relative to the ghost house.
+func (g *Ghost) House() HouseState {
+	return g.house
+}
+
+// SetHouse places the ghost in the given house state.
+func (g *Ghost) SetHouse(h HouseState) {
+	g.house = h
+}
+
+// Release lets a waiting ghost leave the house.
+func (g *Ghost) Release() {
+	if g.house == InHouse {
+		g.house = LeavingHouse
+	}
+}
+
+// moveInHouse moves a ghost that is inside or passing the house door and
+// reports whether it did. Ghosts outside the house are left alone.
+func (g *Ghost) moveInHouse(w World) bool {
+	switch g.house {
+	case InHouse:
+		g.bob(w.Maze)
+	case LeavingHouse:
+		exit, ok := w.Maze.HouseExit()
+		if !ok || g.position == toPosition(exit) {
+			g.house = OutsideHouse
+			g.direction = Left
+			return true
+		}
+		g.stepThroughHouse(toPosition(exit), w.Maze)
+		if g.position == toPosition(exit) {
+			g.house = OutsideHouse
+			g.direction = Left
+		}
+	case EnteringHouse:
+		target := g.reviveTile(w.Maze)
+		if g.position != target {
+			g.stepThroughHouse(target, w.Maze)
+		}
+		if g.position == target {
+			g.state = g.RoamState(w.Mode)
+			g.house = LeavingHouse
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// bob moves a waiting ghost up and down inside the house.
+func (g *Ghost) bob(m *maze.Maze) {
+	if g.direction != Up && g.direction != Down {
+		g.direction = Up
+	}
+	for i := 0; i < 2; i++ {
+		next := g.position.moveIn(g.direction)
+		if m.InHouse(next.X, next.Y) {
+			g.position = next
+			return
+		}
+		g.Reverse()
+	}
+}
+
+// reviveTile returns where eaten eyes turn back into a ghost: the ghost's
+// home if it lies inside the house, otherwise the middle of the house.
+func (g *Ghost) reviveTile(m *maze.Maze) Position {
+	if m.InHouse(g.home.X, g.home.Y) {
+		return g.home
+	}
+	if c, ok := m.HouseCenter(); ok {
+		return toPosition(c)
+	}
+	return g.home
+}
+
+// stepThroughHouse moves one tile along a shortest path to target through
+// the house door.
+func (g *Ghost) stepThroughHouse(target Position, m *maze.Maze) {
+	graph := pathfind.NewGraph(m, true)
+	if next, ok := graph.Distances(toPoint(target)).Next(toPoint(g.position)); ok {
+		g.direction = directionTo(g.position, toPosition(next), graph)
+		g.position = toPosition(next)
+	}
+}
+
+func toPosition(p maze.Point) Position {
+	return Position{X: p.X, Y: p.Y}
+}
+
+func toPoint(p Position) maze.Point {
+	return maze.Point{X: p.X, Y: p.Y}
+}
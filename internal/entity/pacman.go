@@ -1,7 +1,8 @@
 package entity
 
 import (
-	tea "github.com/charmbracelet/bubbletea"
+	"time"
+
 	"github.com/vinser/pacmanai/internal/maze"
 )
 
@@ -18,6 +19,22 @@ const (
 	Right
 )
 
+// offset returns the unit move of the direction.
+func (d Direction) offset() maze.Point {
+	switch d {
+	case Up:
+		return maze.Point{X: 0, Y: -1}
+	case Down:
+		return maze.Point{X: 0, Y: 1}
+	case Left:
+		return maze.Point{X: -1, Y: 0}
+	case Right:
+		return maze.Point{X: 1, Y: 0}
+	default:
+		return maze.Point{}
+	}
+}
+
 // Position represents coordinates on the map.
 type Position struct {
 	X, Y int
@@ -28,6 +45,9 @@ type Pacman struct {
 	home      Position
 	position  Position
 	direction Direction
+	next      Direction
+	turning   bool
+	motion    Motion
 	lives     int
 	// You can add more fields here, e.g., animation frame, lives, etc.
 }
@@ -47,6 +67,11 @@ func (p *Pacman) Home() Position {
 	return p.home
 }
 
+// SetHome moves Pacman's starting position.
+func (p *Pacman) SetHome(home Position) {
+	p.home = home
+}
+
 // Pos returns Pacman's current position.
 func (p *Pacman) Pos() Position {
 	return p.position
@@ -57,9 +82,16 @@ func (p *Pacman) Dir() Direction {
 	return p.direction
 }
 
-// SetPos sets Pacman's position explicitly.
+// SetPos sets Pacman's position explicitly, dropping sub-tile progress.
 func (p *Pacman) SetPos(pos Position) {
 	p.position = pos
+	p.motion.Stop()
+}
+
+// Advance accumulates dt of travel at speed percent and returns the number
+// of tiles Pacman moves this tick.
+func (p *Pacman) Advance(speed int, dt time.Duration) int {
+	return p.motion.Advance(speed, dt)
 }
 
 // NextPos returns the position Pacman would move to based on direction.
@@ -78,37 +110,29 @@ func (p *Pacman) NextPos() Position {
 	return pos
 }
 
-// Move attempts to move Pacman if the next tile is not a wall.
+// Queue buffers a turn that is taken as soon as the corridor in that
+// direction opens up.
+func (p *Pacman) Queue(dir Direction) {
+	p.next = dir
+	p.turning = true
+}
+
+// Move applies a buffered turn if possible and then attempts to move
+// Pacman if the next tile is not a wall.
 func (p *Pacman) Move(m *maze.Maze) {
-	next := p.NextPos()
-
-	// Handle tunnel wrapping
-	if m.IsTunnelRow(next.Y) {
-		if next.X < 0 {
-			next.X = m.Width() - 1
-		} else if next.X >= m.Width() {
-			next.X = 0
-		}
+	if p.turning && canMoveTo(p.position, p.next, m) {
+		p.direction = p.next
+		p.turning = false
 	}
 
-	tile, err := m.TileAt(next.X, next.Y)
-	if err == nil && tile != maze.Wall {
+	if next, ok := NextTile(p.position, p.direction, m); ok {
 		p.position = next
 	}
 }
 
-// HandleInput updates Pacman's direction based on user input.
-func (p *Pacman) HandleInput(msg tea.KeyMsg) {
-	switch msg.String() {
-	case "up", "w":
-		p.direction = Up
-	case "down", "s":
-		p.direction = Down
-	case "left", "a":
-		p.direction = Left
-	case "right", "d":
-		p.direction = Right
-	}
+// SetDirection updates Pacman's movement direction.
+func (p *Pacman) SetDirection(dir Direction) {
+	p.direction = dir
 }
 
 // Lives returns Pacman's remaining lives.
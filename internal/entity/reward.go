@@ -0,0 +1,224 @@
+package entity
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/vinser/pacmanai/internal/maze"
+	"github.com/vinser/pacmanai/internal/pathfind"
+)
+
+// RewardKind identifies what a reward term is paid for.
+type RewardKind int
+
+const (
+	RewardDot RewardKind = iota
+	RewardPowerPellet
+	RewardGhost
+	RewardFruit
+	RewardDeath
+	RewardLevelClear
+	RewardStep
+	RewardShaping
+)
+
+var rewardNames = []string{"dot", "power_pellet", "ghost", "fruit", "death", "level_clear", "step", "shaping"}
+
+// String returns the lowercase name of the reward kind.
+func (k RewardKind) String() string {
+	if k >= 0 && int(k) < len(rewardNames) {
+		return rewardNames[k]
+	}
+	return "reward"
+}
+
+// RewardConfig defines the reward a learning agent receives. It is paid
+// next to the arcade score and never changes it.
+type RewardConfig struct {
+	// Dot, PowerPellet, Ghost and Fruit scale the points those events score.
+	Dot         float64 `json:"dot"`
+	PowerPellet float64 `json:"power_pellet"`
+	Ghost       float64 `json:"ghost"`
+	Fruit       float64 `json:"fruit"`
+	// Death is paid when Pac-Man loses a life and is usually negative.
+	Death float64 `json:"death"`
+	// LevelClear is paid when the last dot of a level is eaten.
+	LevelClear float64 `json:"level_clear"`
+	// Step is paid on every tick of play; a small negative value
+	// rewards clearing levels quickly.
+	Step float64 `json:"step"`
+	// Shaping scales the potential -(steps to the nearest dot). Its change
+	// is paid as Gamma*new - old, which guides the agent towards food
+	// without changing which policy is optimal.
+	Shaping float64 `json:"shaping"`
+	// Gamma should equal the agent's discount factor.
+	Gamma float64 `json:"gamma"`
+}
+
+// DefaultRewardConfig returns the reward that equals the points scored.
+func DefaultRewardConfig() RewardConfig {
+	return RewardConfig{Dot: 1, PowerPellet: 1, Ghost: 1, Fruit: 1, Gamma: 1}
+}
+
+// ParseRewardConfig reads a reward config in JSON. Fields that are not
+// given keep their DefaultRewardConfig values.
+func ParseRewardConfig(r io.Reader) (RewardConfig, error) {
+	cfg := DefaultRewardConfig()
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return RewardConfig{}, fmt.Errorf("reward config: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return RewardConfig{}, err
+	}
+	return cfg, nil
+}
+
+// LoadRewardConfig reads a reward config from a JSON file.
+func LoadRewardConfig(path string) (RewardConfig, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return RewardConfig{}, err
+	}
+	defer f.Close()
+	cfg, err := ParseRewardConfig(f)
+	if err != nil {
+		return RewardConfig{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Validate reports a config that cannot be used.
+func (c RewardConfig) Validate() error {
+	if c.Gamma < 0 || c.Gamma > 1 {
+		return errors.New("reward config: gamma must be between 0 and 1")
+	}
+	return nil
+}
+
+// RewardEvent is a single term of the reward.
+type RewardEvent struct {
+	Kind  RewardKind
+	Value float64
+	// Points is what the event scored, if anything.
+	Points int
+	Pos    Position
+}
+
+// Reward accumulates the training reward of a game alongside its Score.
+type Reward struct {
+	cfg       RewardConfig
+	total     float64
+	potential float64
+	events    []RewardEvent
+}
+
+func NewReward(cfg RewardConfig) *Reward {
+	return &Reward{cfg: cfg}
+}
+
+// Config returns the config the reward is paid by.
+func (r *Reward) Config() RewardConfig {
+	return r.cfg
+}
+
+// Earn pays for a scoring event, a lost life or a cleared level.
+func (r *Reward) Earn(kind RewardKind, points int, pos Position) {
+	var value float64
+	switch kind {
+	case RewardDot:
+		value = r.cfg.Dot * float64(points)
+	case RewardPowerPellet:
+		value = r.cfg.PowerPellet * float64(points)
+	case RewardGhost:
+		value = r.cfg.Ghost * float64(points)
+	case RewardFruit:
+		value = r.cfg.Fruit * float64(points)
+	case RewardDeath:
+		value = r.cfg.Death
+	case RewardLevelClear:
+		value = r.cfg.LevelClear
+	}
+	r.pay(kind, value, points, pos)
+}
+
+// Tick pays the time penalty of one tick of play.
+func (r *Reward) Tick(pos Position) {
+	r.pay(RewardStep, r.cfg.Step, 0, pos)
+}
+
+// Start sets the shaping potential of the first state of a game without
+// paying for it.
+func (r *Reward) Start(m *maze.Maze, pac Position) {
+	r.potential = r.potentialAt(m, pac)
+}
+
+// Shape pays the change in potential after Pac-Man has moved to pac.
+func (r *Reward) Shape(m *maze.Maze, pac Position) {
+	r.reshape(r.potentialAt(m, pac), pac)
+}
+
+// End pays the change to the zero potential of the final state when the
+// game is over.
+func (r *Reward) End(pac Position) {
+	r.reshape(0, pac)
+}
+
+// Take returns the reward terms paid since the last call, in order, and
+// their sum.
+func (r *Reward) Take() ([]RewardEvent, float64) {
+	events := r.events
+	r.events = nil
+	var sum float64
+	for _, ev := range events {
+		sum += ev.Value
+	}
+	return events, sum
+}
+
+// Total returns the reward paid since the game started.
+func (r *Reward) Total() float64 {
+	return r.total
+}
+
+func (r *Reward) reshape(next float64, pos Position) {
+	r.pay(RewardShaping, r.cfg.Gamma*next-r.potential, 0, pos)
+	r.potential = next
+}
+
+// potentialAt returns the shaping potential with Pac-Man at pac: minus the
+// scaled number of steps to the nearest dot or pellet, or zero when none
+// can be reached.
+func (r *Reward) potentialAt(m *maze.Maze, pac Position) float64 {
+	if r.cfg.Shaping == 0 {
+		return 0
+	}
+	d, ok := nearestFood(m, maze.Point{X: pac.X, Y: pac.Y})
+	if !ok {
+		return 0
+	}
+	return -r.cfg.Shaping * float64(d)
+}
+
+// pay records a nonzero reward term.
+func (r *Reward) pay(kind RewardKind, value float64, points int, pos Position) {
+	if value == 0 {
+		return
+	}
+	r.total += value
+	r.events = append(r.events, RewardEvent{Kind: kind, Value: value, Points: points, Pos: pos})
+}
+
+// nearestFood returns the number of steps from start to the closest dot or
+// power pellet.
+func nearestFood(m *maze.Maze, start maze.Point) (int, bool) {
+	path, ok := pathfind.NewGraph(m, false).Nearest(start, func(p maze.Point) bool {
+		t, _ := m.TileAt(p.X, p.Y)
+		return t == maze.Dot || t == maze.PowerPellet
+	}, nil)
+	return len(path), ok
+}
@@ -0,0 +1,124 @@
+package entity
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/vinser/pacmanai/internal/maze"
+)
+
+func TestRewardWeights(t *testing.T) {
+	r := NewReward(RewardConfig{
+		Dot: 2, PowerPellet: 3, Ghost: 0.5, Fruit: 0,
+		Death: -50, LevelClear: 100, Step: -0.25, Gamma: 1,
+	})
+	pos := Position{X: 3, Y: 4}
+	r.Earn(RewardDot, 10, pos)
+	r.Earn(RewardPowerPellet, 50, pos)
+	r.Earn(RewardGhost, 400, pos)
+	r.Earn(RewardFruit, 100, pos) // weighted zero, so not paid
+	r.Earn(RewardDeath, 0, pos)
+	r.Earn(RewardLevelClear, 0, pos)
+	r.Tick(pos)
+
+	events, sum := r.Take()
+	want := []RewardEvent{
+		{Kind: RewardDot, Value: 20, Points: 10, Pos: pos},
+		{Kind: RewardPowerPellet, Value: 150, Points: 50, Pos: pos},
+		{Kind: RewardGhost, Value: 200, Points: 400, Pos: pos},
+		{Kind: RewardDeath, Value: -50, Pos: pos},
+		{Kind: RewardLevelClear, Value: 100, Pos: pos},
+		{Kind: RewardStep, Value: -0.25, Pos: pos},
+	}
+	if !slices.Equal(events, want) {
+		t.Errorf("events %+v, want %+v", events, want)
+	}
+	if sum != 419.75 || r.Total() != 419.75 {
+		t.Errorf("sum %v and total %v, want 419.75", sum, r.Total())
+	}
+	if events, sum := r.Take(); events != nil || sum != 0 {
+		t.Errorf("second Take = %v %v, want nothing", events, sum)
+	}
+}
+
+func TestDefaultRewardIsScore(t *testing.T) {
+	r := NewReward(DefaultRewardConfig())
+	r.Earn(RewardDot, 10, Position{})
+	r.Earn(RewardGhost, 1600, Position{})
+	r.Earn(RewardDeath, 0, Position{})
+	r.Tick(Position{})
+	if _, sum := r.Take(); sum != 1610 {
+		t.Errorf("default reward %v, want the 1610 points scored", sum)
+	}
+}
+
+// shapingMaze is a corridor with food five and six steps from the spawn,
+// and a closed room on the bottom row with no food at all.
+const shapingMaze = `#########
+#C    ..#
+#########
+#   #####
+#########
+`
+
+func TestRewardShaping(t *testing.T) {
+	m, err := maze.Parse(strings.NewReader(shapingMaze))
+	if err != nil {
+		t.Fatal(err)
+	}
+	const gamma = 0.9
+	// step moves Pac-Man and returns the shaping term paid for it.
+	step := func(t *testing.T, r *Reward, x, y int) float64 {
+		t.Helper()
+		r.Shape(m, Position{X: x, Y: y})
+		events, sum := r.Take()
+		for _, ev := range events {
+			if ev.Kind != RewardShaping {
+				t.Errorf("shaping paid a %v term", ev.Kind)
+			}
+		}
+		return sum
+	}
+	near := func(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+
+	r := NewReward(RewardConfig{Shaping: 2, Gamma: gamma})
+	r.Start(m, Position{X: 1, Y: 1})
+	if events, _ := r.Take(); events != nil {
+		t.Fatalf("Start paid %+v", events)
+	}
+	// Potentials are -2 times the steps to the nearest dot: -10 at the
+	// spawn and -8 one step closer.
+	if got, want := step(t, r, 2, 1), gamma*-8-(-10); !near(got, want) || got <= 0 {
+		t.Errorf("stepping towards food paid %v, want %v > 0", got, want)
+	}
+	if got, want := step(t, r, 1, 1), gamma*-10-(-8); !near(got, want) || got >= 0 {
+		t.Errorf("stepping away from food paid %v, want %v < 0", got, want)
+	}
+	if got, want := step(t, r, 1, 1), gamma*-10-(-10); !near(got, want) {
+		t.Errorf("standing still paid %v, want %v", got, want)
+	}
+	// The final state has zero potential.
+	r.End(Position{X: 1, Y: 1})
+	if _, got := r.Take(); got != 10 {
+		t.Errorf("the end paid %v, want 10", got)
+	}
+
+	// Without food in reach the potential is zero.
+	r.Start(m, Position{X: 1, Y: 3})
+	if got := step(t, r, 2, 3); got != 0 {
+		t.Errorf("moving without food in reach paid %v, want 0", got)
+	}
+
+	// A zero weight pays no shaping at all.
+	r = NewReward(RewardConfig{Gamma: gamma})
+	r.Start(m, Position{X: 1, Y: 1})
+	if got := step(t, r, 2, 1); got != 0 {
+		t.Errorf("unweighted shaping paid %v", got)
+	}
+	r.End(Position{X: 2, Y: 1})
+	if events, _ := r.Take(); events != nil {
+		t.Errorf("unweighted end paid %+v", events)
+	}
+}
@@ -4,14 +4,56 @@ type Score struct {
 	value             int
 	high              int
 	eatenGhostsStreak int
+	// nextBonus is the score that awards the next extra life, or 0 when
+	// none is left. bonusEvery repeats the bonus after that many points.
+	nextBonus  int
+	bonusEvery int
+	crossed    []int
 }
 
+// defaultBonusLife is the score that earns the one bonus life of the arcade.
+const defaultBonusLife = 10000
+
 func NewScore() *Score {
 	return &Score{}
 }
 
 func (s *Score) Add(points int) {
 	s.value += points
+	s.checkBonus()
+}
+
+// SetBonusLife awards an extra life when the score reaches threshold and
+// then every interval points after it. A zero threshold selects the arcade's
+// 10,000 and a negative one disables extra lives; a zero interval awards the
+// life only once.
+func (s *Score) SetBonusLife(threshold, interval int) {
+	if threshold == 0 {
+		threshold = defaultBonusLife
+	}
+	s.nextBonus = max(threshold, 0)
+	s.bonusEvery = max(interval, 0)
+	s.checkBonus()
+}
+
+// checkBonus records every bonus threshold the score has reached.
+func (s *Score) checkBonus() {
+	for s.nextBonus > 0 && s.value >= s.nextBonus {
+		s.crossed = append(s.crossed, s.nextBonus)
+		if s.bonusEvery == 0 {
+			s.nextBonus = 0
+		} else {
+			s.nextBonus += s.bonusEvery
+		}
+	}
+}
+
+// TakeCrossings returns the extra-life thresholds reached since the last
+// call, in order.
+func (s *Score) TakeCrossings() []int {
+	crossed := s.crossed
+	s.crossed = nil
+	return crossed
 }
 
 func (s *Score) Get() int {
@@ -26,7 +68,7 @@ func (s *Score) Reset() {
 // Call when Pac-Man eats a frightened ghost
 func (s *Score) AddGhostPoints() {
 	points := 200 << s.eatenGhostsStreak // 200, 400, 800, 1600
-	s.value += points
+	s.Add(points)
 	if s.eatenGhostsStreak < 3 {
 		s.eatenGhostsStreak++
 	}
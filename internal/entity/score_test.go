@@ -0,0 +1,50 @@
+package entity
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTakeCrossings(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold int
+		interval  int
+		adds      []int
+		want      []int
+	}{
+		{"default threshold", 0, 0, []int{9990, 10}, []int{10000}},
+		{"below threshold", 0, 0, []int{9999}, nil},
+		{"disabled", -1, 5000, []int{100000}, nil},
+		{"once", 5000, 0, []int{5000, 5000, 20000}, []int{5000}},
+		{"repeated", 5000, 2000, []int{5000, 2000, 1999}, []int{5000, 7000}},
+		// One big jump crosses several thresholds at once.
+		{"one jump", 5000, 2000, []int{10000}, []int{5000, 7000, 9000}},
+		{"one jump once", 5000, 0, []int{10000}, []int{5000}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewScore()
+			s.SetBonusLife(tt.threshold, tt.interval)
+			for _, p := range tt.adds {
+				s.Add(p)
+			}
+			if got := s.TakeCrossings(); !slices.Equal(got, tt.want) {
+				t.Errorf("crossings %v, want %v", got, tt.want)
+			}
+			if got := s.TakeCrossings(); got != nil {
+				t.Errorf("crossings %v taken twice", got)
+			}
+		})
+	}
+}
+
+func TestSetBonusLifeAfterScore(t *testing.T) {
+	// Thresholds the score already passed are awarded at once.
+	s := NewScore()
+	s.Add(12000)
+	s.SetBonusLife(5000, 3000)
+	if got, want := s.TakeCrossings(), []int{5000, 8000, 11000}; !slices.Equal(got, want) {
+		t.Errorf("crossings %v, want %v", got, want)
+	}
+}
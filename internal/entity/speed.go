@@ -0,0 +1,31 @@
+package entity
+
+import "time"
+
+// FullSpeedInterval is the time an entity moving at 100% speed takes to
+// cross one tile.
+const FullSpeedInterval = 160 * time.Millisecond
+
+// tileCost is the progress needed to cross one tile, in speed percent
+// times travel time.
+const tileCost = 100 * FullSpeedInterval
+
+// Motion turns a speed in percent of full speed into whole-tile steps,
+// carrying the sub-tile remainder over to later ticks.
+type Motion struct {
+	progress time.Duration
+}
+
+// Advance adds dt of travel at speed percent and returns the number of
+// tiles to move now.
+func (m *Motion) Advance(speed int, dt time.Duration) int {
+	m.progress += time.Duration(speed) * dt
+	n := m.progress / tileCost
+	m.progress -= n * tileCost
+	return int(n)
+}
+
+// Stop discards any partial progress towards the next tile.
+func (m *Motion) Stop() {
+	m.progress = 0
+}
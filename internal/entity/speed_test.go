@@ -0,0 +1,65 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+const tick = 50 * time.Millisecond
+
+func TestMotionAdvance(t *testing.T) {
+	tests := []struct {
+		name   string
+		speeds []int
+		dt     time.Duration
+		want   []int
+	}{
+		// A tile takes 160ms at full speed, so 50ms ticks cross one every
+		// 3.2 ticks and the remainder carries over.
+		{"full speed", []int{100, 100, 100, 100, 100, 100, 100, 100}, tick, []int{0, 0, 0, 1, 0, 0, 1, 0}},
+		{"slow", []int{40, 40, 40, 40, 40, 40, 40, 40, 40}, tick, []int{0, 0, 0, 0, 0, 0, 0, 1, 0}},
+		{"several tiles at once", []int{200, 150}, 400 * time.Millisecond, []int{5, 3}},
+		{"stopped", []int{0, 0, 0}, tick, []int{0, 0, 0}},
+		// Progress made at the old speed counts at the new one.
+		{"speed up mid-tile", []int{100, 100, 200}, tick, []int{0, 0, 1}},
+		{"slow down mid-tile", []int{200, 50, 50, 50, 50}, tick, []int{0, 0, 0, 1, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m Motion
+			for i, speed := range tt.speeds {
+				if got := m.Advance(speed, tt.dt); got != tt.want[i] {
+					t.Fatalf("call %d at %d%%: moved %d tiles, want %d", i+1, speed, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMotionKeepsFractions(t *testing.T) {
+	// Over a long run the tiles moved match the distance travelled, for
+	// speeds that do not divide a tile evenly and change every tick.
+	var m Motion
+	var travelled time.Duration
+	tiles := 0
+	for i := 0; i < 1000; i++ {
+		speed := []int{75, 80, 85, 95, 105}[i%5]
+		tiles += m.Advance(speed, tick)
+		travelled += time.Duration(speed) * tick
+		if want := int(travelled / tileCost); tiles != want {
+			t.Fatalf("after %d ticks moved %d tiles, want %d", i+1, tiles, want)
+		}
+	}
+}
+
+func TestMotionStop(t *testing.T) {
+	var m Motion
+	m.Advance(100, 150*time.Millisecond)
+	m.Stop()
+	if got := m.Advance(100, 150*time.Millisecond); got != 0 {
+		t.Errorf("moved %d tiles 150ms after Stop, want 0", got)
+	}
+	if got := m.Advance(100, 10*time.Millisecond); got != 1 {
+		t.Errorf("moved %d tiles 160ms after Stop, want 1", got)
+	}
+}
@@ -0,0 +1,131 @@
+package entity
+
+import (
+	"math/rand"
+
+	"github.com/vinser/pacmanai/internal/maze"
+)
+
+// GhostView is a read-only description of a ghost.
+type GhostView struct {
+	Type  GhostType
+	Pos   Position
+	Dir   Direction
+	State GhostState
+	Home  Position
+}
+
+// World is a read-only snapshot of the game that ghost decisions are based
+// on. Maze is shared with the game and must not be modified.
+type World struct {
+	Maze      *maze.Maze
+	Pacman    Position
+	PacmanDir Direction
+	Ghosts    []GhostView
+	PowerMode bool
+	// Mode is the scatter or chase state roaming ghosts currently follow.
+	Mode GhostState
+	Rand *rand.Rand
+}
+
+// ghost returns the first ghost of type t, if present.
+func (w World) ghost(t GhostType) (GhostView, bool) {
+	for _, g := range w.Ghosts {
+		if g.Type == t {
+			return g, true
+		}
+	}
+	return GhostView{}, false
+}
+
+// Targeter picks the tile a ghost heads for while chasing Pac-Man.
+type Targeter interface {
+	Target(self GhostView, w World) Position
+}
+
+// targeters holds the arcade chase personality of each ghost.
+var targeters = map[GhostType]Targeter{
+	Blinky: blinkyTargeter{},
+	Pinky:  pinkyTargeter{},
+	Inky:   inkyTargeter{},
+	Clyde:  clydeTargeter{},
+}
+
+// TargeterFor returns the chase strategy of the given ghost type.
+func TargeterFor(t GhostType) Targeter {
+	if tg, ok := targeters[t]; ok {
+		return tg
+	}
+	return blinkyTargeter{}
+}
+
+// blinkyTargeter aims straight at Pac-Man.
+type blinkyTargeter struct{}
+
+func (blinkyTargeter) Target(_ GhostView, w World) Position {
+	return w.Pacman
+}
+
+// pinkyTargeter aims four tiles ahead of Pac-Man to cut him off.
+type pinkyTargeter struct{}
+
+func (pinkyTargeter) Target(_ GhostView, w World) Position {
+	return w.Pacman.ahead(w.PacmanDir, 4)
+}
+
+// inkyTargeter doubles the vector from Blinky to the tile two ahead of Pac-Man.
+type inkyTargeter struct{}
+
+func (inkyTargeter) Target(_ GhostView, w World) Position {
+	pivot := w.Pacman.ahead(w.PacmanDir, 2)
+	blinky, ok := w.ghost(Blinky)
+	if !ok {
+		return pivot
+	}
+	b := blinky.Pos
+	return Position{X: 2*pivot.X - b.X, Y: 2*pivot.Y - b.Y}
+}
+
+// clydeTargeter chases Pac-Man from afar but retreats to his corner when
+// closer than eight tiles.
+type clydeTargeter struct{}
+
+func (clydeTargeter) Target(self GhostView, w World) Position {
+	if distSq(self.Pos, w.Pacman) < 8*8 {
+		return ScatterCorner(Clyde, w.Maze)
+	}
+	return w.Pacman
+}
+
+// ScatterCorner returns the maze corner the given ghost type retreats to.
+// Mazes may mark their own targets; otherwise each ghost uses a corner.
+func ScatterCorner(t GhostType, m *maze.Maze) Position {
+	if p, ok := m.ScatterTarget(t.String()); ok {
+		return toPosition(p)
+	}
+	right, bottom := m.Width()-1, m.Height()-1
+	switch t {
+	case Blinky:
+		return Position{X: right, Y: 0}
+	case Pinky:
+		return Position{X: 0, Y: 0}
+	case Inky:
+		return Position{X: right, Y: bottom}
+	default:
+		return Position{X: 0, Y: bottom}
+	}
+}
+
+// ahead returns the position n tiles away in direction d.
+func (p Position) ahead(d Direction, n int) Position {
+	for i := 0; i < n; i++ {
+		p = p.moveIn(d)
+	}
+	return p
+}
+
+// distSq returns the squared Euclidean distance between two positions.
+func distSq(a, b Position) int {
+	dx, dy := a.X-b.X, a.Y-b.Y
+	return dx*dx + dy*dy
+}
@@ -0,0 +1,150 @@
+// Package env exposes the game as a reinforcement learning environment
+// with Gym-style reset and step calls, and serves it to trainers in other
+// processes over a JSON-lines protocol.
+package env
+
+import (
+	"errors"
+
+	"github.com/vinser/pacmanai/internal/entity"
+	"github.com/vinser/pacmanai/internal/level"
+	"github.com/vinser/pacmanai/internal/observe"
+	"github.com/vinser/pacmanai/internal/render"
+	"github.com/vinser/pacmanai/internal/sim"
+)
+
+// Config holds the rules every episode of an environment is played with.
+type Config struct {
+	// GhostAI selects the ghost brains in the format of entity.ParseBrains.
+	// Brains are created afresh for every episode.
+	GhostAI string
+	// Levels lists the rules of every level. When nil, the arcade
+	// progression is used.
+	Levels level.Table
+	// Mazes returns the maze source of an episode started with seed. When
+	// nil, the level table's mazes are used.
+	Mazes func(seed int64) (level.MazeSource, error)
+	// Reward defines the reward of every step. When nil, the reward is the
+	// score gained.
+	Reward *entity.RewardConfig
+	// MaxSteps ends an episode after that many steps. Zero means no limit.
+	MaxSteps int
+	// Encoding adds a tensor encoding of the board to every observation.
+	// When nil, observations carry the tile grid only.
+	Encoding *observe.Encoding
+}
+
+// Env is a single environment. It is not safe for concurrent use.
+type Env struct {
+	cfg   Config
+	game  *sim.Game
+	steps int
+}
+
+// New returns an environment that plays by cfg. Reset must be called
+// before the first Step.
+func New(cfg Config) *Env {
+	return &Env{cfg: cfg}
+}
+
+// Step is the outcome of a single Env.Step.
+type Step struct {
+	Observation Observation `json:"observation"`
+	Reward      float64     `json:"reward"`
+	Done        bool        `json:"done"`
+	Info        Info        `json:"info"`
+}
+
+// Reset starts a new episode from seed and returns its first observation.
+func (e *Env) Reset(seed int64) (Observation, Info, error) {
+	brains, err := entity.ParseBrains(e.cfg.GhostAI)
+	if err != nil {
+		return Observation{}, Info{}, err
+	}
+	var mazes level.MazeSource
+	if e.cfg.Mazes != nil {
+		if mazes, err = e.cfg.Mazes(seed); err != nil {
+			return Observation{}, Info{}, err
+		}
+	}
+	e.game = sim.NewGame(sim.Config{
+		Seed:   seed,
+		Levels: e.cfg.Levels,
+		Mazes:  mazes,
+		Brains: brains,
+		Reward: e.cfg.Reward,
+	})
+	e.steps = 0
+	obs := e.game.Observe()
+	info := newInfo(obs, nil, nil)
+	info.Actions = actionNames
+	info.Tiles = tileNames
+	if e.cfg.Encoding != nil {
+		info.TensorLabels = e.cfg.Encoding.Labels
+	}
+	return e.observation(obs), info, nil
+}
+
+// Step applies action and advances the game until the agent has a decision
+// to make again. Phases without control, such as the level intro and the
+// pause after losing a life, are played through with no action, so one
+// Step may cover many game ticks.
+func (e *Env) Step(action sim.Action) (Step, error) {
+	if e.game == nil {
+		return Step{}, errors.New("environment not reset")
+	}
+	if e.game.Phase() == sim.PhaseGameOver || e.truncated() {
+		return Step{}, errors.New("episode is over; reset to start a new one")
+	}
+	if action < sim.NoAction || action > sim.MoveRight {
+		return Step{}, errors.New("invalid action")
+	}
+	var (
+		events  []sim.Event
+		rewards []entity.RewardEvent
+		reward  float64
+	)
+	res := e.game.Step(action)
+	for {
+		events = append(events, res.Events...)
+		rewards = append(rewards, res.Rewards...)
+		reward += res.Reward
+		if res.Done || res.Observation.Phase == sim.PhasePlaying {
+			break
+		}
+		res = e.game.Step(sim.NoAction)
+	}
+	e.steps++
+	info := newInfo(res.Observation, events, rewards)
+	info.Truncated = !res.Done && e.truncated()
+	return Step{
+		Observation: e.observation(res.Observation),
+		Reward:      reward,
+		Done:        res.Done || info.Truncated,
+		Info:        info,
+	}, nil
+}
+
+// Render returns the board as plain text.
+func (e *Env) Render() (string, error) {
+	if e.game == nil {
+		return "", errors.New("environment not reset")
+	}
+	g := e.game
+	return render.RenderBoard(g.Maze(), g.Pacman(), g.Ghosts(), g.Fruit()), nil
+}
+
+// observation converts a game snapshot and adds the configured encoding.
+func (e *Env) observation(obs sim.Observation) Observation {
+	out := newObservation(obs)
+	if e.cfg.Encoding != nil {
+		t := e.cfg.Encoding.Encode(obs)
+		out.Tensor = &t
+	}
+	return out
+}
+
+// truncated reports whether the episode has reached its step limit.
+func (e *Env) truncated() bool {
+	return e.cfg.MaxSteps > 0 && e.steps >= e.cfg.MaxSteps
+}
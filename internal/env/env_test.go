@@ -0,0 +1,229 @@
+package env
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/vinser/pacmanai/internal/level"
+	"github.com/vinser/pacmanai/internal/maze"
+	"github.com/vinser/pacmanai/internal/observe"
+)
+
+// reply holds the fields of every kind of response line.
+type reply struct {
+	Error       string      `json:"error"`
+	Text        string      `json:"text"`
+	Observation Observation `json:"observation"`
+	Info        Info        `json:"info"`
+	Reward      float64     `json:"reward"`
+	Done        bool        `json:"done"`
+}
+
+// client talks to Serve over an in-memory connection.
+type client struct {
+	t    *testing.T
+	conn net.Conn
+	in   *bufio.Reader
+	done chan error
+}
+
+// dial starts Serve with cfg and returns a client connected to it.
+func dial(t *testing.T, cfg Config) *client {
+	t.Helper()
+	server, conn := net.Pipe()
+	c := &client{t: t, conn: conn, in: bufio.NewReader(conn), done: make(chan error, 1)}
+	go func() {
+		c.done <- Serve(server, cfg)
+		server.Close()
+	}()
+	t.Cleanup(func() { conn.Close() })
+	return c
+}
+
+// send writes a request line and decodes the response line.
+func (c *client) send(line string) reply {
+	c.t.Helper()
+	if _, err := c.conn.Write([]byte(line + "\n")); err != nil {
+		c.t.Fatalf("%s: %v", line, err)
+	}
+	b, err := c.in.ReadBytes('\n')
+	if err != nil {
+		c.t.Fatalf("%s: %v", line, err)
+	}
+	var r reply
+	if err := json.Unmarshal(b, &r); err != nil {
+		c.t.Fatalf("%s: response %q: %v", line, b, err)
+	}
+	return r
+}
+
+// sendError sends a request that must fail with an error containing want.
+func (c *client) sendError(line, want string) {
+	c.t.Helper()
+	if r := c.send(line); !strings.Contains(r.Error, want) {
+		c.t.Errorf("%s: error %q, want one containing %q", line, r.Error, want)
+	}
+}
+
+func TestServe(t *testing.T) {
+	c := dial(t, Config{})
+
+	c.sendError(`{"cmd":"step","action":1}`, "not reset")
+	c.sendError(`{"cmd":"render"}`, "not reset")
+	c.sendError(`not json`, "invalid request")
+	c.sendError(`{"cmd":"step","action":"up"}`, "invalid request")
+	c.sendError(`{"cmd":"jump"}`, `unknown command "jump"`)
+
+	r := c.send(`{"cmd":"reset","seed":7}`)
+	if r.Error != "" {
+		t.Fatal(r.Error)
+	}
+	m := maze.LoadDefault()
+	obs := r.Observation
+	if len(obs.Grid) != m.Height() || len(obs.Grid[0]) != m.Width() {
+		t.Fatalf("grid is %dx%d, want %dx%d", len(obs.Grid[0]), len(obs.Grid), m.Width(), m.Height())
+	}
+	if p, _ := m.Spawn(maze.SpawnPacman); obs.Pacman.X != p.X || obs.Pacman.Y != p.Y {
+		t.Errorf("Pac-Man at (%d,%d), want the spawn %v", obs.Pacman.X, obs.Pacman.Y, p)
+	}
+	if len(obs.Ghosts) != 4 || obs.Lives < 1 || obs.Level != 1 || obs.RemainingDots == 0 {
+		t.Errorf("unexpected first observation: %+v", obs)
+	}
+	if !slices.Equal(r.Info.Actions, actionNames) || !slices.Equal(r.Info.Tiles, tileNames) {
+		t.Errorf("reset info names actions %v and tiles %v", r.Info.Actions, r.Info.Tiles)
+	}
+	if obs.Tensor != nil || r.Info.TensorLabels != nil {
+		t.Error("observation has a tensor without an encoding")
+	}
+
+	// The intro is played through, so the first step is already in play.
+	r = c.send(`{"cmd":"step","action":4}`)
+	if r.Error != "" {
+		t.Fatal(r.Error)
+	}
+	if r.Done || r.Info.Phase != "playing" || r.Observation.Tick <= obs.Tick {
+		t.Errorf("step: done=%v phase=%q tick %d after %d", r.Done, r.Info.Phase, r.Observation.Tick, obs.Tick)
+	}
+	if r.Info.Actions != nil || r.Info.Events == nil || r.Info.Rewards == nil {
+		t.Errorf("step info: %+v", r.Info)
+	}
+
+	c.sendError(`{"cmd":"step","action":5}`, "invalid action")
+	c.sendError(`{"cmd":"step","action":-1}`, "invalid action")
+
+	r = c.send(`{"cmd":"render"}`)
+	if r.Error != "" {
+		t.Fatal(r.Error)
+	}
+	if lines := strings.Split(strings.TrimSuffix(r.Text, "\n"), "\n"); len(lines) != m.Height() {
+		t.Errorf("render has %d lines, want %d:\n%s", len(lines), m.Height(), r.Text)
+	}
+
+	// Blank lines get no response; close ends the session cleanly.
+	if _, err := c.conn.Write([]byte("\n  \n{\"cmd\":\"close\"}\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-c.done; err != nil {
+		t.Errorf("Serve: %v", err)
+	}
+}
+
+func TestServeSameSeed(t *testing.T) {
+	play := func() []reply {
+		c := dial(t, Config{GhostAI: "random"})
+		replies := []reply{c.send(`{"cmd":"reset","seed":3}`)}
+		for _, a := range "1111444422223333" {
+			replies = append(replies, c.send(`{"cmd":"step","action":`+string(a)+`}`))
+		}
+		return replies
+	}
+	a, b := play(), play()
+	for i := range a {
+		ja, _ := json.Marshal(a[i])
+		jb, _ := json.Marshal(b[i])
+		if string(ja) != string(jb) {
+			t.Fatalf("reply %d differs:\n%s\n%s", i, ja, jb)
+		}
+	}
+}
+
+func TestServeMaxSteps(t *testing.T) {
+	c := dial(t, Config{MaxSteps: 2})
+	c.send(`{"cmd":"reset","seed":1}`)
+	if r := c.send(`{"cmd":"step","action":0}`); r.Done {
+		t.Fatal("episode ended after one step")
+	}
+	r := c.send(`{"cmd":"step","action":0}`)
+	if !r.Done || !r.Info.Truncated {
+		t.Errorf("second step: done=%v truncated=%v, want both", r.Done, r.Info.Truncated)
+	}
+	c.sendError(`{"cmd":"step","action":0}`, "episode is over")
+	if r := c.send(`{"cmd":"reset","seed":1}`); r.Error != "" {
+		t.Errorf("reset after the limit: %s", r.Error)
+	}
+}
+
+// portalMaze has a tunnel through row 3, a two-way portal 1 and a one-way
+// portal 2.
+const portalMaze = `@oneway 2
+#########
+#C..1..B#
+#.#####.#
+T.I.P.Y.T
+#2#####2#
+#..1....#
+#########
+`
+
+func TestServePortalsAndTunnels(t *testing.T) {
+	m, err := maze.Parse(strings.NewReader(portalMaze))
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := level.FixedMaze(m)
+	enc, err := observe.ParseEncoding("planes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := dial(t, Config{
+		Mazes:    func(int64) (level.MazeSource, error) { return src, nil },
+		Encoding: &enc,
+	})
+	r := c.send(`{"cmd":"reset","seed":1}`)
+	if r.Error != "" {
+		t.Fatal(r.Error)
+	}
+	obs := r.Observation
+	for _, p := range []maze.Point{{X: 4, Y: 1}, {X: 3, Y: 5}, {X: 1, Y: 4}, {X: 7, Y: 4}} {
+		if got := tileNames[obs.Grid[p.Y][p.X]]; got != "portal" {
+			t.Errorf("%v is %q, want portal", p, got)
+		}
+	}
+	for _, x := range []int{0, 8} {
+		if got := tileNames[obs.Grid[3][x]]; got != "tunnel" {
+			t.Errorf("(%d,3) is %q, want tunnel", x, got)
+		}
+	}
+	want := []Portal{
+		{X: 4, Y: 1, ToX: 3, ToY: 5},
+		{X: 1, Y: 4, ToX: 7, ToY: 4},
+		{X: 3, Y: 5, ToX: 4, ToY: 1},
+	}
+	if !slices.Equal(obs.Portals, want) {
+		t.Errorf("portals %+v, want %+v", obs.Portals, want)
+	}
+
+	if obs.Tensor == nil || !slices.Equal(obs.Tensor.Shape, []int{int(observe.NumPlanes), 7, 9}) {
+		t.Fatalf("tensor %v, want the planes of the board", obs.Tensor)
+	}
+	if !slices.Equal(r.Info.TensorLabels, observe.PlaneNames()) {
+		t.Errorf("tensor labels %v", r.Info.TensorLabels)
+	}
+	if r = c.send(`{"cmd":"step","action":0}`); r.Observation.Tensor == nil {
+		t.Error("step observation has no tensor")
+	}
+}
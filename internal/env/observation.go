@@ -0,0 +1,194 @@
+package env
+
+import (
+	"github.com/vinser/pacmanai/internal/entity"
+	"github.com/vinser/pacmanai/internal/maze"
+	"github.com/vinser/pacmanai/internal/observe"
+	"github.com/vinser/pacmanai/internal/sim"
+)
+
+// actionNames lists the actions by their numeric value.
+var actionNames = []string{"none", "up", "down", "left", "right"}
+
+// tileNames lists the tile codes of Observation.Grid by their value. The
+// first five are the values of maze.Tile; empty floor that slows ghosts
+// down or carries a portal has a code of its own.
+var tileNames = []string{"wall", "dot", "empty", "power_pellet", "door", "tunnel", "portal"}
+
+const (
+	tileTunnel = 5
+	tilePortal = 6
+)
+
+var (
+	directionNames = []string{"up", "down", "left", "right"}
+	stateNames     = []string{"chase", "scatter", "frightened", "eaten"}
+	phaseNames     = []string{"playing", "respawning", "game_over", "level_intro"}
+	eventNames     = []string{"dot", "power_pellet", "ghost", "fruit", "extra_life", "life_lost", "level_cleared", "game_over"}
+)
+
+// Observation is the game state sent to an agent.
+type Observation struct {
+	// Grid holds a tile code per row and column; Info.Tiles names them.
+	Grid [][]int `json:"grid"`
+	// Portals lists where each portal entrance on the grid leads.
+	Portals       []Portal `json:"portals,omitempty"`
+	Pacman        Actor    `json:"pacman"`
+	Ghosts        []Actor  `json:"ghosts"`
+	Fruit         *Fruit   `json:"fruit,omitempty"`
+	Score         int      `json:"score"`
+	Lives         int      `json:"lives"`
+	Level         int      `json:"level"`
+	RemainingDots int      `json:"remaining_dots"`
+	PowerMode     bool     `json:"power_mode"`
+	// PowerLeftMs is how many milliseconds frightened ghosts stay blue.
+	PowerLeftMs int64 `json:"power_left_ms"`
+	Tick        int   `json:"tick"`
+	// Tensor is the board in the environment's encoding, if it has one.
+	// Info.TensorLabels names the entries along its first axis.
+	Tensor *observe.Tensor `json:"tensor,omitempty"`
+}
+
+// Actor is Pac-Man or a ghost on the board. Name and State are set for
+// ghosts only.
+type Actor struct {
+	Name  string `json:"name,omitempty"`
+	X     int    `json:"x"`
+	Y     int    `json:"y"`
+	Dir   string `json:"dir"`
+	State string `json:"state,omitempty"`
+}
+
+// Portal is a portal entrance and the tile moving onto it lands on.
+type Portal struct {
+	X   int `json:"x"`
+	Y   int `json:"y"`
+	ToX int `json:"to_x"`
+	ToY int `json:"to_y"`
+}
+
+// Fruit is the bonus fruit on the board.
+type Fruit struct {
+	Kind   string `json:"kind"`
+	X      int    `json:"x"`
+	Y      int    `json:"y"`
+	Points int    `json:"points"`
+}
+
+// Info carries diagnostics that are not part of the observation.
+type Info struct {
+	Phase string `json:"phase"`
+	// Events lists what happened during the step, in order.
+	Events []Event `json:"events"`
+	// Rewards lists the terms the step's reward is made of.
+	Rewards []Reward `json:"rewards"`
+	// Truncated is set when the episode ended on the step limit rather
+	// than by losing the last life.
+	Truncated bool `json:"truncated,omitempty"`
+	// Actions and Tiles name the action values and tile codes. They are
+	// sent on reset only.
+	Actions []string `json:"actions,omitempty"`
+	Tiles   []string `json:"tiles,omitempty"`
+	// TensorLabels names the planes or features of Observation.Tensor. It
+	// is sent on reset only.
+	TensorLabels []string `json:"tensor_labels,omitempty"`
+}
+
+// Event is a reward-relevant occurrence within a step.
+type Event struct {
+	Kind   string `json:"kind"`
+	Points int    `json:"points,omitempty"`
+	X      int    `json:"x"`
+	Y      int    `json:"y"`
+}
+
+// Reward is one term of a step's reward.
+type Reward struct {
+	Kind  string  `json:"kind"`
+	Value float64 `json:"value"`
+	X     int     `json:"x"`
+	Y     int     `json:"y"`
+}
+
+// newObservation converts a game snapshot.
+func newObservation(obs sim.Observation) Observation {
+	m := obs.Maze
+	grid := make([][]int, m.Height())
+	var portals []Portal
+	for y := range grid {
+		grid[y] = make([]int, m.Width())
+		for x := range grid[y] {
+			p := maze.Point{X: x, Y: y}
+			t, _ := m.TileAt(x, y)
+			grid[y][x] = int(t)
+			if _, ok := m.PortalGlyph(p); ok && t == maze.Empty {
+				grid[y][x] = tilePortal
+			} else if m.IsTunnel(x, y) && t == maze.Empty {
+				grid[y][x] = tileTunnel
+			}
+			if exit, ok := m.Portal(p); ok {
+				portals = append(portals, Portal{X: x, Y: y, ToX: exit.X, ToY: exit.Y})
+			}
+		}
+	}
+	out := Observation{
+		Grid:          grid,
+		Portals:       portals,
+		Pacman:        Actor{X: obs.Pacman.X, Y: obs.Pacman.Y, Dir: name(directionNames, int(obs.PacmanDir))},
+		Ghosts:        make([]Actor, 0, len(obs.Ghosts)),
+		Score:         obs.Score,
+		Lives:         obs.Lives,
+		Level:         obs.Level,
+		RemainingDots: obs.RemainingDots,
+		PowerMode:     obs.PowerMode,
+		PowerLeftMs:   obs.PowerLeft.Milliseconds(),
+		Tick:          obs.Tick,
+	}
+	for _, g := range obs.Ghosts {
+		out.Ghosts = append(out.Ghosts, Actor{
+			Name:  g.Type.String(),
+			X:     g.Pos.X,
+			Y:     g.Pos.Y,
+			Dir:   name(directionNames, int(g.Dir)),
+			State: name(stateNames, int(g.State)),
+		})
+	}
+	if f := obs.Fruit; f != nil {
+		out.Fruit = &Fruit{Kind: f.Kind().String(), X: f.Pos().X, Y: f.Pos().Y, Points: f.Points()}
+	}
+	return out
+}
+
+// newInfo builds the info of a step that ended in obs.
+func newInfo(obs sim.Observation, events []sim.Event, rewards []entity.RewardEvent) Info {
+	info := Info{
+		Phase:   name(phaseNames, int(obs.Phase)),
+		Events:  make([]Event, 0, len(events)),
+		Rewards: make([]Reward, 0, len(rewards)),
+	}
+	for _, ev := range events {
+		info.Events = append(info.Events, Event{
+			Kind:   name(eventNames, int(ev.Kind)),
+			Points: ev.Points,
+			X:      ev.Pos.X,
+			Y:      ev.Pos.Y,
+		})
+	}
+	for _, r := range rewards {
+		info.Rewards = append(info.Rewards, Reward{
+			Kind:  r.Kind.String(),
+			Value: r.Value,
+			X:     r.Pos.X,
+			Y:     r.Pos.Y,
+		})
+	}
+	return info
+}
+
+// name looks up the protocol name of an enum value.
+func name(names []string, i int) string {
+	if i >= 0 && i < len(names) {
+		return names[i]
+	}
+	return "unknown"
+}
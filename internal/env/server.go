@@ -0,0 +1,138 @@
+package env
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/vinser/pacmanai/internal/sim"
+)
+
+// maxLine bounds the length of a single request line.
+const maxLine = 1 << 20
+
+// Request is one line of the protocol sent by a client:
+//
+//	{"cmd":"reset","seed":42}
+//	{"cmd":"step","action":3}
+//	{"cmd":"render"}
+//	{"cmd":"close"}
+type Request struct {
+	Cmd    string     `json:"cmd"`
+	Seed   int64      `json:"seed"`
+	Action sim.Action `json:"action"`
+}
+
+// resetReply answers a reset request.
+type resetReply struct {
+	Observation Observation `json:"observation"`
+	Info        Info        `json:"info"`
+}
+
+// renderReply answers a render request.
+type renderReply struct {
+	Text string `json:"text"`
+}
+
+// errorReply answers a request that failed. The connection stays usable.
+type errorReply struct {
+	Error string `json:"error"`
+}
+
+// Serve runs the protocol on a single connection with its own environment
+// until the client closes it or sends close. Every request line gets one
+// response line.
+func Serve(rw io.ReadWriter, cfg Config) error {
+	e := New(cfg)
+	in := bufio.NewScanner(rw)
+	in.Buffer(make([]byte, 0, 4096), maxLine)
+	out := json.NewEncoder(rw)
+	for in.Scan() {
+		line := strings.TrimSpace(in.Text())
+		if line == "" {
+			continue
+		}
+		var req Request
+		if err := json.Unmarshal([]byte(line), &req); err != nil {
+			if err := out.Encode(errorReply{Error: "invalid request: " + err.Error()}); err != nil {
+				return err
+			}
+			continue
+		}
+		if req.Cmd == "close" {
+			return nil
+		}
+		if err := out.Encode(e.handle(req)); err != nil {
+			return err
+		}
+	}
+	return in.Err()
+}
+
+// handle executes one request and returns the reply to send.
+func (e *Env) handle(req Request) any {
+	switch req.Cmd {
+	case "reset":
+		obs, info, err := e.Reset(req.Seed)
+		if err != nil {
+			return errorReply{Error: err.Error()}
+		}
+		return resetReply{Observation: obs, Info: info}
+	case "step":
+		st, err := e.Step(req.Action)
+		if err != nil {
+			return errorReply{Error: err.Error()}
+		}
+		return st
+	case "render":
+		text, err := e.Render()
+		if err != nil {
+			return errorReply{Error: err.Error()}
+		}
+		return renderReply{Text: text}
+	default:
+		return errorReply{Error: fmt.Sprintf("unknown command %q", req.Cmd)}
+	}
+}
+
+// Listen opens a listener on addr. An address of the form "unix:path"
+// listens on a Unix socket, replacing a stale socket file left behind by
+// an earlier server; any other address is a TCP host:port.
+func Listen(addr string) (net.Listener, error) {
+	path, ok := strings.CutPrefix(addr, "unix:")
+	if !ok {
+		return net.Listen("tcp", addr)
+	}
+	if fi, err := os.Stat(path); err == nil && fi.Mode()&fs.ModeSocket != 0 {
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+	return net.Listen("unix", path)
+}
+
+// ServeListener accepts connections on l and serves each one concurrently
+// with its own environment. It returns when l is closed.
+func ServeListener(l net.Listener, cfg Config, logf func(format string, args ...any)) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		go func() {
+			defer conn.Close()
+			if err := Serve(conn, cfg); err != nil && logf != nil {
+				logf("%s: %v", conn.RemoteAddr(), err)
+			}
+		}()
+	}
+}
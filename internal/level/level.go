@@ -3,40 +3,183 @@ package level
 import (
 	"time"
 
+	"github.com/vinser/pacmanai/internal/entity"
 	"github.com/vinser/pacmanai/internal/maze"
 )
 
+// defaultFlashes is how many warning flashes frightened ghosts give when a
+// level spec leaves it out.
+const defaultFlashes = 5
+
+// defaultEatenSpeed is the speed percent of eaten ghosts returning to the
+// house when a level spec leaves it out.
+const defaultEatenSpeed = 200
+
 type Config struct {
-	Index             int
-	Maze              *maze.Maze
-	RemainingDots     int
-	GhostTickInterval time.Duration
+	Index         int
+	Maze          *maze.Maze
+	TotalDots     int
+	RemainingDots int
+	// Speeds are percentages of entity.FullSpeedInterval per tile.
+	PacmanSpeed        int
+	PacmanFrightSpeed  int
+	GhostSpeed         int
+	GhostFrightSpeed   int
+	GhostTunnelSpeed   int
+	EatenSpeed         int
+	ElroySpeed1        int
+	ElroySpeed2        int
+	FrightenedDuration time.Duration
+	// FrightFlashes is how many times frightened ghosts flash white before
+	// they recover.
+	FrightFlashes int
+	// Waves lists alternating scatter and chase durations, starting with
+	// scatter. The mode after the last wave lasts for the rest of the level.
+	Waves       []time.Duration
+	Fruit       entity.FruitKind
+	FruitPoints int
+	ElroyDots1  int
+	ElroyDots2  int
+}
+
+// MazeSource returns a fresh maze for the given level index.
+type MazeSource func(index int) *maze.Maze
+
+// DefaultMazes plays every level on the built-in default maze.
+func DefaultMazes(int) *maze.Maze {
+	return maze.LoadDefault()
 }
 
-// Create initializes a new level with its configuration and dot count.
-func Create(index int) *Config {
-	loader := maze.LoadDefault // TODO: Replace with level-specific loader if available
-	m := loader()
+// FixedMaze plays every level on a fresh copy of m.
+func FixedMaze(m *maze.Maze) MazeSource {
+	return func(int) *maze.Maze {
+		return m.Clone()
+	}
+}
+
+// genAttempts is how many seeds GeneratedMazes tries for a level before
+// giving up on generating it.
+const genAttempts = 16
+
+// GeneratedMazes plays every level on a freshly generated maze derived
+// from seed and the level index. It fails if the parameters cannot produce
+// a valid maze. A level whose seeds keep producing invalid mazes is played
+// on the default maze instead, so a game never stops halfway.
+func GeneratedMazes(seed int64, width, height int, opts maze.GenOptions) (MazeSource, error) {
+	gen := func(index int) (*maze.Maze, error) {
+		var err error
+		for i := 0; i < genAttempts; i++ {
+			// Retries are spaced far apart so they do not reuse the seeds
+			// of later levels.
+			var m *maze.Maze
+			if m, err = maze.Generate(seed+int64(index)+int64(i)<<32, width, height, opts); err == nil {
+				return m, nil
+			}
+		}
+		return nil, err
+	}
+	if _, err := gen(1); err != nil {
+		return nil, err
+	}
+	return func(index int) *maze.Maze {
+		m, err := gen(index)
+		if err != nil {
+			return maze.LoadDefault()
+		}
+		return m
+	}, nil
+}
+
+// Create initializes a new level from its table entry. A nil table selects
+// Arcade. The maze comes from src, or from the spec's built-in maze (the
+// default maze if none) when src is nil.
+func Create(index int, table Table, src MazeSource) *Config {
+	if table == nil {
+		table = Arcade
+	}
+	spec := table.Spec(index)
+	if src == nil {
+		src = builtinMazes(spec.Maze)
+	}
+	m := src(index)
 	dotCount := countDots(m)
 
+	// Tables are validated when loaded; fall back to a cherry otherwise.
+	fruit, _ := entity.ParseFruit(spec.Fruit)
+
+	flashes := defaultFlashes
+	if spec.Flashes != nil {
+		flashes = *spec.Flashes
+	}
+
+	waves := make([]time.Duration, len(spec.Waves))
+	for i, w := range spec.Waves {
+		waves[i] = time.Duration(w)
+	}
+
 	return &Config{
-		Index:             index,
-		Maze:              m,
-		RemainingDots:     dotCount,
-		GhostTickInterval: ghostInterval(index),
+		Index:              index,
+		Maze:               m,
+		TotalDots:          dotCount,
+		RemainingDots:      dotCount,
+		PacmanSpeed:        spec.PacmanSpeed,
+		PacmanFrightSpeed:  orDefault(spec.PacmanFrightSpeed, spec.PacmanSpeed),
+		GhostSpeed:         spec.GhostSpeed,
+		GhostFrightSpeed:   orDefault(spec.GhostFrightSpeed, spec.GhostSpeed),
+		GhostTunnelSpeed:   orDefault(spec.GhostTunnelSpeed, spec.GhostSpeed),
+		EatenSpeed:         orDefault(spec.EatenSpeed, defaultEatenSpeed),
+		ElroySpeed1:        orDefault(spec.ElroySpeed1, spec.GhostSpeed+5),
+		ElroySpeed2:        orDefault(spec.ElroySpeed2, spec.GhostSpeed+10),
+		FrightenedDuration: time.Duration(spec.Frightened),
+		FrightFlashes:      flashes,
+		Waves:              waves,
+		Fruit:              fruit,
+		FruitPoints:        spec.FruitPoints,
+		ElroyDots1:         spec.ElroyDots1,
+		ElroyDots2:         spec.ElroyDots2,
 	}
 }
 
-// ghostInterval returns the ghost movement interval based on level.
-func ghostInterval(level int) time.Duration {
-	base := 500 * time.Millisecond
-	step := 25 * time.Millisecond
-	calculated := base - time.Duration(level-1)*step
+// Spawn returns where the named entity starts on the level's maze. A maze
+// without that spawn point puts ghosts in the middle of the house and
+// anyone else on the first open tile.
+func (c *Config) Spawn(name string) entity.Position {
+	if p, ok := c.Maze.Spawn(name); ok {
+		return entity.Position{X: p.X, Y: p.Y}
+	}
+	if p, ok := c.Maze.HouseCenter(); ok && name != maze.SpawnPacman {
+		return entity.Position{X: p.X, Y: p.Y}
+	}
+	for y := 0; y < c.Maze.Height(); y++ {
+		for x := 0; x < c.Maze.Width(); x++ {
+			if tile, _ := c.Maze.TileAt(x, y); tile.Walkable() {
+				return entity.Position{X: x, Y: y}
+			}
+		}
+	}
+	return entity.Position{}
+}
+
+// builtinMazes returns a source for the named built-in maze.
+func builtinMazes(name string) MazeSource {
+	if name == "" {
+		return DefaultMazes
+	}
+	return func(int) *maze.Maze {
+		m, err := maze.LoadBuiltin(name)
+		if err != nil {
+			panic("level: " + err.Error())
+		}
+		return m
+	}
+}
 
-	if calculated < 100*time.Millisecond {
-		return 100 * time.Millisecond
+// orDefault returns v, or fallback if v is left out.
+func orDefault(v, fallback int) int {
+	if v == 0 {
+		return fallback
 	}
-	return calculated
+	return v
 }
 
 // countDots scans the maze and returns the number of dot/power-pellet tiles.
@@ -0,0 +1,107 @@
+package level
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vinser/pacmanai/internal/maze"
+)
+
+func TestGeneratedMazes(t *testing.T) {
+	if _, err := GeneratedMazes(1, 14, 13, maze.DefaultGenOptions(13)); err == nil {
+		t.Error("GeneratedMazes accepted an even width")
+	}
+
+	src, err := GeneratedMazes(3, 21, 17, maze.DefaultGenOptions(17))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for index := 1; index <= 10; index++ {
+		m := src(index)
+		if m == nil || m.Width() != 21 || m.Height() != 17 {
+			t.Fatalf("level %d: got no 21x17 maze", index)
+		}
+		if problems := maze.Validate(m); maze.HasErrors(problems) {
+			t.Errorf("level %d: %v", index, problems)
+		}
+	}
+}
+
+func TestFlashes(t *testing.T) {
+	table, err := ParseTable(strings.NewReader(`[
+		{"pacman_speed": 80, "ghost_speed": 75, "frightened": "6s", "waves": ["7s"], "fruit": "cherry"},
+		{"pacman_speed": 80, "ghost_speed": 75, "frightened": "6s", "flashes": 0, "waves": ["7s"], "fruit": "cherry"},
+		{"pacman_speed": 80, "ghost_speed": 75, "frightened": "6s", "flashes": 2, "waves": ["7s"], "fruit": "cherry"}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for index, want := range map[int]int{1: defaultFlashes, 2: 0, 3: 2} {
+		if got := Create(index, table, DefaultMazes).FrightFlashes; got != want {
+			t.Errorf("level %d: FrightFlashes = %d, want %d", index, got, want)
+		}
+	}
+
+	if _, err := ParseTable(strings.NewReader(`[{"pacman_speed": 80, "ghost_speed": 75, "flashes": -1, "fruit": "cherry"}]`)); err == nil {
+		t.Error("ParseTable accepted a negative flash count")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	const valid = `"pacman_speed": 80, "ghost_speed": 75, "frightened": "6s", "waves": ["7s", "20s"], "fruit": "cherry"`
+	tests := []struct {
+		name  string
+		input string
+		ok    bool
+	}{
+		{"valid", `[{` + valid + `}]`, true},
+		{"empty table", `[]`, false},
+		{"no waves", `[{"pacman_speed": 80, "ghost_speed": 75, "frightened": "6s", "fruit": "cherry"}]`, false},
+		{"empty waves", `[{"pacman_speed": 80, "ghost_speed": 75, "frightened": "6s", "waves": [], "fruit": "cherry"}]`, false},
+		{"zero wave", `[{"pacman_speed": 80, "ghost_speed": 75, "frightened": "6s", "waves": ["7s", "0s"], "fruit": "cherry"}]`, false},
+		{"no waves on a later level", `[{` + valid + `}, {"pacman_speed": 80, "ghost_speed": 75, "fruit": "cherry"}]`, false},
+		{"slow Pac-Man", `[{` + valid + `, "pacman_speed": 0}]`, false},
+		{"fast ghosts", `[{` + valid + `, "ghost_speed": 201}]`, false},
+		{"negative tunnel speed", `[{` + valid + `, "ghost_tunnel_speed": -1}]`, false},
+		{"fast eyes", `[{` + valid + `, "eaten_speed": 201}]`, false},
+		{"negative fright", `[{` + valid + `, "frightened": "-1s"}]`, false},
+		{"unknown fruit", `[{` + valid + `, "fruit": "banana"}]`, false},
+		{"negative fruit points", `[{` + valid + `, "fruit_points": -100}]`, false},
+		{"Elroy stages swapped", `[{` + valid + `, "elroy_dots_1": 10, "elroy_dots_2": 20}]`, false},
+		{"unknown maze", `[{` + valid + `, "maze": "nowhere"}]`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTable(strings.NewReader(tt.input))
+			if tt.ok && err != nil {
+				t.Errorf("ParseTable: %v", err)
+			}
+			if !tt.ok && err == nil {
+				t.Error("ParseTable accepted the table")
+			}
+		})
+	}
+	if err := Arcade.Validate(); err != nil {
+		t.Errorf("arcade table: %v", err)
+	}
+}
+
+func TestEatenSpeed(t *testing.T) {
+	table, err := ParseTable(strings.NewReader(`[
+		{"pacman_speed": 80, "ghost_speed": 75, "frightened": "6s", "waves": ["7s"], "fruit": "cherry"},
+		{"pacman_speed": 80, "ghost_speed": 75, "frightened": "6s", "eaten_speed": 150, "waves": ["7s"], "fruit": "cherry"}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for index, want := range map[int]int{1: defaultEatenSpeed, 2: 150} {
+		if got := Create(index, table, DefaultMazes).EatenSpeed; got != want {
+			t.Errorf("level %d: EatenSpeed = %d, want %d", index, got, want)
+		}
+	}
+	for index := 1; index <= len(Arcade); index++ {
+		if got := Create(index, nil, DefaultMazes).EatenSpeed; got != defaultEatenSpeed {
+			t.Errorf("arcade level %d: EatenSpeed = %d, want %d", index, got, defaultEatenSpeed)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package level
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/vinser/pacmanai/internal/entity"
+	"github.com/vinser/pacmanai/internal/maze"
+)
+
+// Duration is a time.Duration written as a string such as "7s" in level
+// files.
+type Duration time.Duration
+
+// MarshalJSON implements json.Marshaler.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"7s\": %w", err)
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Spec describes the rules of a single level. Speeds are percentages of
+// full speed as in the arcade.
+type Spec struct {
+	// Maze names a built-in maze. Empty selects the game's maze source.
+	Maze        string `json:"maze,omitempty"`
+	PacmanSpeed int    `json:"pacman_speed"`
+	GhostSpeed  int    `json:"ghost_speed"`
+	// PacmanFrightSpeed, GhostFrightSpeed and GhostTunnelSpeed apply while
+	// ghosts are frightened and to ghosts on tunnel tiles. Left out, they
+	// default to the normal speed.
+	PacmanFrightSpeed int `json:"pacman_fright_speed,omitempty"`
+	GhostFrightSpeed  int `json:"ghost_fright_speed,omitempty"`
+	GhostTunnelSpeed  int `json:"ghost_tunnel_speed,omitempty"`
+	// EatenSpeed is how fast the eyes of eaten ghosts return to the house.
+	// Left out, they move at twice full speed.
+	EatenSpeed int `json:"eaten_speed,omitempty"`
+	// Frightened is how long ghosts stay blue after a power pellet.
+	Frightened Duration `json:"frightened"`
+	// Flashes is how many times frightened ghosts flash white before they
+	// recover. Left out, they flash five times; zero turns the warning off.
+	Flashes *int `json:"flashes,omitempty"`
+	// Waves lists alternating scatter and chase durations, starting with
+	// scatter. The mode after the last wave lasts for the rest of the level,
+	// so a level needs at least one wave.
+	Waves       []Duration `json:"waves"`
+	Fruit       string     `json:"fruit"`
+	FruitPoints int        `json:"fruit_points"`
+	// ElroyDots1 and ElroyDots2 are the remaining-dot counts at which
+	// Blinky turns into Cruise Elroy stage one and two.
+	ElroyDots1 int `json:"elroy_dots_1"`
+	ElroyDots2 int `json:"elroy_dots_2"`
+	// ElroySpeed1 and ElroySpeed2 are Blinky's speeds in each stage. Left
+	// out, they are 5 and 10 percent above the ghost speed as in the arcade.
+	ElroySpeed1 int `json:"elroy_speed_1,omitempty"`
+	ElroySpeed2 int `json:"elroy_speed_2,omitempty"`
+}
+
+// Table lists level specs in order. Levels beyond the end of the table
+// repeat its last entry.
+type Table []Spec
+
+// Spec returns the spec of the level with the given 1-based index.
+func (t Table) Spec(index int) Spec {
+	if index < 1 {
+		index = 1
+	}
+	if index > len(t) {
+		index = len(t)
+	}
+	return t[index-1]
+}
+
+// ParseTable reads a JSON array of level specs from r and checks it.
+func ParseTable(r io.Reader) (Table, error) {
+	var t Table
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&t); err != nil {
+		return nil, err
+	}
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// LoadTable reads a level table file from disk.
+func LoadTable(path string) (Table, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	t, err := ParseTable(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return t, nil
+}
+
+// Validate reports the first inconsistency in the table.
+func (t Table) Validate() error {
+	if len(t) == 0 {
+		return errors.New("level table is empty")
+	}
+	for i, s := range t {
+		if s.PacmanSpeed < 1 || s.PacmanSpeed > 200 || s.GhostSpeed < 1 || s.GhostSpeed > 200 {
+			return fmt.Errorf("level %d: speeds must be between 1 and 200 percent", i+1)
+		}
+		for _, v := range []int{s.PacmanFrightSpeed, s.GhostFrightSpeed, s.GhostTunnelSpeed, s.EatenSpeed, s.ElroySpeed1, s.ElroySpeed2} {
+			if v < 0 || v > 200 {
+				return fmt.Errorf("level %d: speeds must be between 1 and 200 percent", i+1)
+			}
+		}
+		if s.Frightened < 0 {
+			return fmt.Errorf("level %d: negative frightened duration", i+1)
+		}
+		if s.Flashes != nil && *s.Flashes < 0 {
+			return fmt.Errorf("level %d: negative flash count", i+1)
+		}
+		if len(s.Waves) == 0 {
+			return fmt.Errorf("level %d: no waves", i+1)
+		}
+		for _, w := range s.Waves {
+			if w <= 0 {
+				return fmt.Errorf("level %d: waves must be positive", i+1)
+			}
+		}
+		if _, err := entity.ParseFruit(s.Fruit); err != nil {
+			return fmt.Errorf("level %d: %w", i+1, err)
+		}
+		if s.FruitPoints < 0 {
+			return fmt.Errorf("level %d: negative fruit points", i+1)
+		}
+		if s.ElroyDots2 > s.ElroyDots1 {
+			return fmt.Errorf("level %d: Elroy stage two must start at or below stage one", i+1)
+		}
+		if s.Maze != "" {
+			if _, err := maze.LoadBuiltin(s.Maze); err != nil {
+				return fmt.Errorf("level %d: maze %q: %w", i+1, s.Maze, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Arcade is the level progression of the original arcade game.
+var Arcade = arcadeTable()
+
+func arcadeTable() Table {
+	s := Duration(time.Second)
+	waves1 := []Duration{7 * s, 20 * s, 7 * s, 20 * s, 5 * s, 20 * s, 5 * s}
+	waves2 := []Duration{7 * s, 20 * s, 7 * s, 20 * s, 5 * s, 1033 * s, s / 60}
+	waves5 := []Duration{5 * s, 20 * s, 5 * s, 20 * s, 5 * s, 1037 * s, s / 60}
+
+	// Pac-Man normal and frightened, ghost normal, frightened and tunnel.
+	type speeds [5]int
+	sp1 := speeds{80, 90, 75, 50, 40}
+	sp2 := speeds{90, 95, 85, 55, 45}
+	sp5 := speeds{100, 100, 95, 60, 50}
+	sp21 := speeds{90, 90, 95, 60, 50}
+
+	row := func(fruit string, points int, sp speeds, elroy1 int, fright Duration, waves []Duration) Spec {
+		// Levels with a single second of fright flash only three times,
+		// and levels without fright not at all.
+		flashes := 5
+		switch fright {
+		case s:
+			flashes = 3
+		case 0:
+			flashes = 0
+		}
+		return Spec{
+			PacmanSpeed:       sp[0],
+			PacmanFrightSpeed: sp[1],
+			GhostSpeed:        sp[2],
+			GhostFrightSpeed:  sp[3],
+			GhostTunnelSpeed:  sp[4],
+			EatenSpeed:        defaultEatenSpeed,
+			Frightened:        fright,
+			Flashes:           &flashes,
+			Waves:             waves,
+			Fruit:             fruit,
+			FruitPoints:       points,
+			ElroyDots1:        elroy1,
+			ElroyDots2:        elroy1 / 2,
+		}
+	}
+	return Table{
+		row("cherry", 100, sp1, 20, 6*s, waves1),
+		row("strawberry", 300, sp2, 30, 5*s, waves2),
+		row("orange", 500, sp2, 40, 4*s, waves2),
+		row("orange", 500, sp2, 40, 3*s, waves2),
+		row("apple", 700, sp5, 40, 2*s, waves5),
+		row("apple", 700, sp5, 50, 5*s, waves5),
+		row("melon", 1000, sp5, 50, 2*s, waves5),
+		row("melon", 1000, sp5, 50, 2*s, waves5),
+		row("galaxian", 2000, sp5, 60, 1*s, waves5),
+		row("galaxian", 2000, sp5, 60, 5*s, waves5),
+		row("bell", 3000, sp5, 60, 2*s, waves5),
+		row("bell", 3000, sp5, 80, 1*s, waves5),
+		row("key", 5000, sp5, 80, 1*s, waves5),
+		row("key", 5000, sp5, 80, 3*s, waves5),
+		row("key", 5000, sp5, 100, 1*s, waves5),
+		row("key", 5000, sp5, 100, 1*s, waves5),
+		row("key", 5000, sp5, 100, 0, waves5),
+		row("key", 5000, sp5, 100, 1*s, waves5),
+		row("key", 5000, sp5, 120, 0, waves5),
+		row("key", 5000, sp5, 120, 0, waves5),
+		row("key", 5000, sp21, 120, 0, waves5),
+	}
+}
@@ -0,0 +1,313 @@
+package maze
+
+import (
+	"bufio"
+	"embed"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Maze text format
+//
+// A maze is a rectangle of equally wide rows, one glyph per tile:
+//
+//	#  wall
+//	.  dot
+//	o  power pellet
+//	   (space) empty floor
+//	C  Pac-Man spawn
+//	B  Blinky spawn
+//	I  Inky spawn
+//	P  Pinky spawn
+//	Y  Clyde spawn
+//	H  ghost house floor
+//	-  ghost house door
+//	F  bonus fruit spot
+//	T  tunnel floor
+//	b  Blinky scatter target (wall)
+//	i  Inky scatter target (wall)
+//	p  Pinky scatter target (wall)
+//	y  Clyde scatter target (wall)
+//	0-9  portal
+//
+// Marker glyphs are empty floor carrying extra meaning; lowercase scatter
+// markers are walls. Spawn markers that touch the house floor are part of
+// the house. Ghosts without a scatter marker retreat to a maze corner.
+// Rows whose first and last tiles are both open wrap around as tunnels, and
+// so do columns open at the top and bottom. Ghosts slow down on tunnel
+// floor. Bonus fruit appears on the F marker only; a maze without one has
+// no fruit.
+//
+// Each portal digit marks exactly two tiles, and moving onto one of them
+// lands on the other. The directive line "@oneway 3" makes portal 3 lead
+// only from its first tile in reading order to the second.
+//
+// Lines starting with ';' are comments and are ignored.
+
+// Spawn point and scatter target names used by the maze format. Ghost
+// names match entity.GhostType.String.
+const (
+	SpawnPacman = "pacman"
+	SpawnBlinky = "blinky"
+	SpawnInky   = "inky"
+	SpawnPinky  = "pinky"
+	SpawnClyde  = "clyde"
+)
+
+// spawnGlyphs maps spawn marker glyphs to spawn point names.
+var spawnGlyphs = map[rune]string{
+	'C': SpawnPacman,
+	'B': SpawnBlinky,
+	'I': SpawnInky,
+	'P': SpawnPinky,
+	'Y': SpawnClyde,
+}
+
+// scatterGlyphs maps scatter marker glyphs to ghost names.
+var scatterGlyphs = map[rune]string{
+	'b': SpawnBlinky,
+	'i': SpawnInky,
+	'p': SpawnPinky,
+	'y': SpawnClyde,
+}
+
+//go:embed mazes/*.txt
+var builtin embed.FS
+
+// Parse reads a maze in text format from r.
+func Parse(r io.Reader) (*Maze, error) {
+	var rows []string
+	var lines []int
+	oneway := map[rune]int{}
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimRight(sc.Text(), "\r")
+		if strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "@") {
+			digit, err := parseDirective(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", n, err)
+			}
+			oneway[digit] = n
+			continue
+		}
+		rows = append(rows, line)
+		lines = append(lines, n)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	// Blank lines at the start and end of a file are not rows.
+	for len(rows) > 0 && rows[0] == "" {
+		rows, lines = rows[1:], lines[1:]
+	}
+	for len(rows) > 0 && rows[len(rows)-1] == "" {
+		rows, lines = rows[:len(rows)-1], lines[:len(lines)-1]
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("maze is empty")
+	}
+
+	width := len([]rune(rows[0]))
+	m := &Maze{
+		width:        width,
+		height:       len(rows),
+		grid:         make([][]Tile, len(rows)),
+		spawns:       map[string]Point{},
+		scatter:      map[string]Point{},
+		inHouse:      map[Point]bool{},
+		tunnels:      map[Point]bool{},
+		portals:      map[Point]Point{},
+		portalGlyphs: map[Point]rune{},
+	}
+	for y, row := range rows {
+		glyphs := []rune(row)
+		if len(glyphs) != width {
+			return nil, fmt.Errorf("line %d: row is %d tiles wide, want %d", lines[y], len(glyphs), width)
+		}
+		m.grid[y] = make([]Tile, width)
+		for x, g := range glyphs {
+			tile, err := m.place(g, Point{X: x, Y: y})
+			if err != nil {
+				return nil, fmt.Errorf("line %d, column %d: %w", lines[y], x+1, err)
+			}
+			m.grid[y][x] = tile
+		}
+		// Tunnel sides must be symmetric (either both open or both closed)
+		if (m.grid[y][0] == Wall) != (m.grid[y][width-1] == Wall) {
+			return nil, fmt.Errorf("line %d: asymmetric tunnel", lines[y])
+		}
+	}
+	for x := 0; x < width; x++ {
+		if (m.grid[0][x] == Wall) != (m.grid[m.height-1][x] == Wall) {
+			return nil, fmt.Errorf("column %d: asymmetric vertical tunnel", x+1)
+		}
+	}
+	if err := m.linkPortals(oneway); err != nil {
+		return nil, err
+	}
+	m.spawnsIntoHouse()
+	return m, nil
+}
+
+// parseDirective reads an "@oneway <digit>" line and returns the digit.
+func parseDirective(line string) (rune, error) {
+	fields := strings.Fields(line)
+	if len(fields) != 2 || fields[0] != "@oneway" {
+		return 0, fmt.Errorf("unknown directive %q", line)
+	}
+	digits := []rune(fields[1])
+	if len(digits) != 1 || digits[0] < '0' || digits[0] > '9' {
+		return 0, fmt.Errorf("portal must be a single digit, got %q", fields[1])
+	}
+	return digits[0], nil
+}
+
+// linkPortals pairs up the portal markers. Portals listed in oneway, keyed
+// by the line of their directive, only lead from the first tile to the
+// second.
+func (m *Maze) linkPortals(oneway map[rune]int) error {
+	ends := map[rune][]Point{}
+	for y := 0; y < m.height; y++ {
+		for x := 0; x < m.width; x++ {
+			p := Point{X: x, Y: y}
+			if g, ok := m.portalGlyphs[p]; ok {
+				ends[g] = append(ends[g], p)
+			}
+		}
+	}
+	for g, line := range oneway {
+		if _, ok := ends[g]; !ok {
+			return fmt.Errorf("line %d: no portal %c in the maze", line, g)
+		}
+	}
+	for g := '0'; g <= '9'; g++ {
+		pts, ok := ends[g]
+		if !ok {
+			continue
+		}
+		if len(pts) != 2 {
+			return fmt.Errorf("portal %c marks %d tiles, want 2", g, len(pts))
+		}
+		m.portals[pts[0]] = pts[1]
+		if _, ok := oneway[g]; !ok {
+			m.portals[pts[1]] = pts[0]
+		}
+	}
+	return nil
+}
+
+// spawnsIntoHouse adds spawn points adjacent to house floor to the house.
+// Spawns are visited in name order so the house tiles come out the same
+// on every parse.
+func (m *Maze) spawnsIntoHouse() {
+	names := make([]string, 0, len(m.spawns))
+	for name := range m.spawns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for grown := true; grown; {
+		grown = false
+		for _, name := range names {
+			p := m.spawns[name]
+			if m.inHouse[p] {
+				continue
+			}
+			for _, d := range []Point{{X: 0, Y: -1}, {X: -1, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 0}} {
+				if m.inHouse[Point{X: p.X + d.X, Y: p.Y + d.Y}] {
+					m.house = append(m.house, p)
+					m.inHouse[p] = true
+					grown = true
+					break
+				}
+			}
+		}
+	}
+}
+
+// place records the meaning of glyph g at p and returns its tile.
+func (m *Maze) place(g rune, p Point) (Tile, error) {
+	switch g {
+	case '#':
+		return Wall, nil
+	case '.':
+		return Dot, nil
+	case 'o':
+		return PowerPellet, nil
+	case ' ':
+		return Empty, nil
+	case '-':
+		return Door, nil
+	case 'H':
+		m.house = append(m.house, p)
+		m.inHouse[p] = true
+		return Empty, nil
+	case 'F':
+		if m.hasFruit {
+			return Empty, fmt.Errorf("second fruit spot (first at %d,%d)", m.fruit.X, m.fruit.Y)
+		}
+		m.fruit, m.hasFruit = p, true
+		return Empty, nil
+	case 'T':
+		m.tunnels[p] = true
+		return Empty, nil
+	}
+	if g >= '0' && g <= '9' {
+		m.portalGlyphs[p] = g
+		return Empty, nil
+	}
+	if name, ok := spawnGlyphs[g]; ok {
+		if prev, dup := m.spawns[name]; dup {
+			return Empty, fmt.Errorf("second %s spawn (first at %d,%d)", name, prev.X, prev.Y)
+		}
+		m.spawns[name] = p
+		return Empty, nil
+	}
+	if name, ok := scatterGlyphs[g]; ok {
+		if prev, dup := m.scatter[name]; dup {
+			return Wall, fmt.Errorf("second %s scatter target (first at %d,%d)", name, prev.X, prev.Y)
+		}
+		m.scatter[name] = p
+		return Wall, nil
+	}
+	return Empty, fmt.Errorf("unknown glyph %q", g)
+}
+
+// Load reads a maze file from disk.
+func Load(path string) (*Maze, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	m, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return m, nil
+}
+
+// LoadFS reads a maze file from a file system such as an embed.FS.
+func LoadFS(fsys fs.FS, name string) (*Maze, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	m, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return m, nil
+}
+
+// LoadBuiltin returns one of the mazes compiled into the binary.
+func LoadBuiltin(name string) (*Maze, error) {
+	return LoadFS(builtin, "mazes/"+name+".txt")
+}
@@ -0,0 +1,54 @@
+package maze
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"empty", "", "maze is empty"},
+		{"only blank lines and comments", "\n; nothing\n\n", "maze is empty"},
+		{"blank row inside", "#####\n\n#C..#\n#####\n", "line 2: row is 0 tiles wide, want 5"},
+		{"ragged row", "#####\n#C.#\n#####\n", "line 2: row is 4 tiles wide, want 5"},
+		{"unknown glyph", "#####\n#C.?#\n#####\n", `line 2, column 4: unknown glyph '?'`},
+		{"second spawn", "#####\n#C.C#\n#####\n", "second pacman spawn"},
+		{"second fruit spot", "#####\n#CFF#\n#####\n", "second fruit spot"},
+		{"second scatter target", "b###b\n#C..#\n#####\n", "second blinky scatter target"},
+		{"asymmetric tunnel", "#####\n C..#\n#####\n", "line 2: asymmetric tunnel"},
+		{"asymmetric vertical tunnel", "## ##\n#C..#\n#####\n", "column 3: asymmetric vertical tunnel"},
+		{"lonely portal", "#####\n#C.1#\n#####\n", "portal 1 marks 1 tiles, want 2"},
+		{"crowded portal", "#######\n#C111.#\n#######\n", "portal 1 marks 3 tiles, want 2"},
+		{"unknown directive", "@twoway 1\n#####\n#1C1#\n#####\n", `line 1: unknown directive "@twoway 1"`},
+		{"directive without digit", "@oneway x\n#####\n#1C1#\n#####\n", `line 1: portal must be a single digit, got "x"`},
+		{"one-way missing portal", "@oneway 3\n#####\n#1C1#\n#####\n", "line 1: no portal 3 in the maze"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.input))
+			if err == nil {
+				t.Fatalf("Parse succeeded, want error containing %q", tt.want)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse error = %q, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSkipsSurroundingBlankLines(t *testing.T) {
+	m, err := Parse(strings.NewReader("\n\n#####\n#C..#\n#####\n\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Width() != 5 || m.Height() != 3 {
+		t.Errorf("size = %dx%d, want 5x3", m.Width(), m.Height())
+	}
+	if p, ok := m.Spawn(SpawnPacman); !ok || p != (Point{X: 1, Y: 1}) {
+		t.Errorf("pacman spawn = %v, %v; want (1,1)", p, ok)
+	}
+}
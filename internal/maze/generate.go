@@ -0,0 +1,362 @@
+package maze
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+// GenOptions tunes the procedural maze generator.
+type GenOptions struct {
+	// TunnelRows lists the rows that wrap around the left and right edges.
+	// Each must be an odd row inside the maze.
+	TunnelRows []int
+	// PowerPellets is the number of power pellets to place. It must be even
+	// so that the maze stays symmetric, and Generate fails if that many do
+	// not fit.
+	PowerPellets int
+	// Loops is the fraction (0 to 1) of the remaining walls between
+	// corridors that are knocked out to add extra loops.
+	Loops float64
+}
+
+// DefaultGenOptions returns arcade-like settings for a maze of the given
+// height: one tunnel near the middle and a power pellet in every corner.
+func DefaultGenOptions(height int) GenOptions {
+	mid := height / 2
+	if mid%2 == 0 {
+		mid--
+	}
+	return GenOptions{
+		TunnelRows:   []int{mid},
+		PowerPellets: 4,
+		Loops:        0.15,
+	}
+}
+
+// generator carves a maze on a lattice of corridor cells placed at odd
+// coordinates. Every carve is mirrored left to right.
+type generator struct {
+	rng    *rand.Rand
+	w, h   int
+	grid   [][]Tile
+	degree map[Point]int
+	// house is the corridor ring around the ghost house (x0,y0)-(x1,y1).
+	x0, y0, x1, y1 int
+}
+
+// Generate builds a left-right symmetric arcade-style maze with a central
+// ghost house and no dead ends. Width and height must be odd and at least
+// 15 and 13. The same seed and options always yield the same maze.
+func Generate(seed int64, width, height int, opts GenOptions) (*Maze, error) {
+	if width < 15 || height < 13 || width%2 == 0 || height%2 == 0 {
+		return nil, fmt.Errorf("maze size %dx%d: want odd width >= 15 and odd height >= 13", width, height)
+	}
+	if opts.PowerPellets < 0 || opts.PowerPellets%2 != 0 {
+		return nil, fmt.Errorf("power pellet count %d: want an even number", opts.PowerPellets)
+	}
+	for _, y := range opts.TunnelRows {
+		if y <= 0 || y >= height-1 || y%2 == 0 {
+			return nil, fmt.Errorf("tunnel row %d: want an odd row between 1 and %d", y, height-2)
+		}
+	}
+
+	g := &generator{
+		rng:    rand.New(rand.NewSource(seed)),
+		w:      width,
+		h:      height,
+		grid:   make([][]Tile, height),
+		degree: map[Point]int{},
+	}
+	for y := range g.grid {
+		g.grid[y] = make([]Tile, width)
+	}
+	g.placeHouse()
+	g.carveTree()
+	g.braid()
+	g.addLoops(opts.Loops)
+
+	m, err := g.finish(opts)
+	if err != nil {
+		return nil, err
+	}
+	if problems := Validate(m); HasErrors(problems) {
+		return nil, fmt.Errorf("generated maze is invalid: %v", problems[0])
+	}
+	return m, nil
+}
+
+// placeHouse chooses the ring of corridor around the central ghost house.
+// Ring lines sit on odd coordinates so they coincide with lattice cells.
+func (g *generator) placeHouse() {
+	g.x0 = g.w/2 - 4
+	if g.x0%2 == 0 {
+		g.x0--
+	}
+	g.x1 = g.w - 1 - g.x0
+	g.y0 = g.h/2 - 3
+	if g.y0%2 == 0 {
+		g.y0--
+	}
+	g.y1 = g.y0 + 6
+
+	for x := g.x0; x < g.x1; x += 2 {
+		g.carve(Point{X: x, Y: g.y0}, Point{X: x + 2, Y: g.y0})
+		g.carve(Point{X: x, Y: g.y1}, Point{X: x + 2, Y: g.y1})
+	}
+	for y := g.y0; y < g.y1; y += 2 {
+		g.carve(Point{X: g.x0, Y: y}, Point{X: g.x0, Y: y + 2})
+		g.carve(Point{X: g.x1, Y: y}, Point{X: g.x1, Y: y + 2})
+	}
+}
+
+// reserved reports whether a lattice cell lies inside the house ring.
+func (g *generator) reserved(c Point) bool {
+	return c.X > g.x0 && c.X < g.x1 && c.Y > g.y0 && c.Y < g.y1
+}
+
+// cells returns every usable lattice cell.
+func (g *generator) cells() []Point {
+	var out []Point
+	for y := 1; y < g.h-1; y += 2 {
+		for x := 1; x < g.w-1; x += 2 {
+			if c := (Point{X: x, Y: y}); !g.reserved(c) {
+				out = append(out, c)
+			}
+		}
+	}
+	return out
+}
+
+// adjacent returns the usable lattice cells next to c.
+func (g *generator) adjacent(c Point) []Point {
+	var out []Point
+	for _, d := range []Point{{X: 0, Y: -2}, {X: -2, Y: 0}, {X: 0, Y: 2}, {X: 2, Y: 0}} {
+		n := Point{X: c.X + d.X, Y: c.Y + d.Y}
+		if n.X > 0 && n.X < g.w-1 && n.Y > 0 && n.Y < g.h-1 && !g.reserved(n) {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+func (g *generator) open(p Point) bool {
+	return g.grid[p.Y][p.X] != Wall
+}
+
+func (g *generator) mirror(p Point) Point {
+	return Point{X: g.w - 1 - p.X, Y: p.Y}
+}
+
+// linked reports whether the wall between neighboring cells a and b is open.
+func (g *generator) linked(a, b Point) bool {
+	return g.open(Point{X: (a.X + b.X) / 2, Y: (a.Y + b.Y) / 2})
+}
+
+// carve opens the passage between neighboring cells a and b and its mirror.
+func (g *generator) carve(a, b Point) {
+	for _, e := range [][2]Point{{a, b}, {g.mirror(a), g.mirror(b)}} {
+		if g.open(e[0]) && g.open(e[1]) && g.linked(e[0], e[1]) {
+			continue
+		}
+		for _, p := range []Point{e[0], e[1], {X: (e[0].X + e[1].X) / 2, Y: (e[0].Y + e[1].Y) / 2}} {
+			g.grid[p.Y][p.X] = Empty
+		}
+		g.degree[e[0]]++
+		g.degree[e[1]]++
+	}
+}
+
+// carveTree connects all cells with a randomized Kruskal spanning tree.
+func (g *generator) carveTree() {
+	parent := map[Point]Point{}
+	var find func(Point) Point
+	find = func(p Point) Point {
+		if q, ok := parent[p]; ok && q != p {
+			r := find(q)
+			parent[p] = r
+			return r
+		}
+		return p
+	}
+	union := func(a, b Point) bool {
+		ra, rb := find(a), find(b)
+		if ra == rb {
+			return false
+		}
+		parent[ra] = rb
+		return true
+	}
+	// The ring is already carved and forms one component.
+	for x := g.x0; x <= g.x1; x += 2 {
+		union(Point{X: x, Y: g.y0}, Point{X: g.x0, Y: g.y0})
+		union(Point{X: x, Y: g.y1}, Point{X: g.x0, Y: g.y0})
+	}
+	for y := g.y0; y <= g.y1; y += 2 {
+		union(Point{X: g.x0, Y: y}, Point{X: g.x0, Y: g.y0})
+		union(Point{X: g.x1, Y: y}, Point{X: g.x0, Y: g.y0})
+	}
+
+	var edges [][2]Point
+	for _, c := range g.cells() {
+		for _, n := range g.adjacent(c) {
+			if n.X > c.X || n.Y > c.Y {
+				edges = append(edges, [2]Point{c, n})
+			}
+		}
+	}
+	g.rng.Shuffle(len(edges), func(i, j int) { edges[i], edges[j] = edges[j], edges[i] })
+	for _, e := range edges {
+		if union(e[0], e[1]) {
+			g.carve(e[0], e[1])
+			m0, m1 := g.mirror(e[0]), g.mirror(e[1])
+			union(m0, m1)
+		}
+	}
+}
+
+// braid removes dead ends by linking every cell with a single passage to
+// one more neighbor, preferring neighbors that are dead ends themselves.
+func (g *generator) braid() {
+	for _, c := range g.cells() {
+		if g.degree[c] != 1 {
+			continue
+		}
+		var options, deadEnds []Point
+		for _, n := range g.adjacent(c) {
+			if g.linked(c, n) {
+				continue
+			}
+			options = append(options, n)
+			if g.degree[n] == 1 {
+				deadEnds = append(deadEnds, n)
+			}
+		}
+		if len(deadEnds) > 0 {
+			options = deadEnds
+		}
+		if len(options) > 0 {
+			g.carve(c, options[g.rng.Intn(len(options))])
+		}
+	}
+}
+
+// addLoops knocks out a fraction of the remaining inner walls.
+func (g *generator) addLoops(fraction float64) {
+	if fraction <= 0 {
+		return
+	}
+	for _, c := range g.cells() {
+		if c.X > g.w/2 {
+			continue // mirrored from the left half
+		}
+		for _, n := range g.adjacent(c) {
+			if (n.X > c.X || n.Y > c.Y) && !g.linked(c, n) && g.rng.Float64() < fraction {
+				g.carve(c, n)
+			}
+		}
+	}
+}
+
+// finish renders the carved lattice into a maze with dots, pellets, the
+// ghost house, tunnels and spawn markers.
+func (g *generator) finish(opts GenOptions) (*Maze, error) {
+	cx := g.w / 2
+	// Pac-Man starts two rows below the house; make sure that tile is open
+	// even when the center column falls between lattice cells.
+	pac := Point{X: cx, Y: g.y1 + 2}
+	if cx%2 == 0 {
+		g.carve(Point{X: cx - 1, Y: pac.Y}, Point{X: cx + 1, Y: pac.Y})
+	}
+
+	rows := make([][]rune, g.h)
+	for y := range rows {
+		rows[y] = make([]rune, g.w)
+		for x := range rows[y] {
+			switch {
+			case !g.open(Point{X: x, Y: y}):
+				rows[y][x] = '#'
+			case x >= g.x0 && x <= g.x1 && y >= g.y0 && y <= g.y1:
+				rows[y][x] = ' ' // no dots around the house
+			default:
+				rows[y][x] = '.'
+			}
+		}
+	}
+
+	// Ghost house: walls with a door in the middle of the top, floor inside.
+	for y := g.y0 + 1; y <= g.y1-1; y++ {
+		for x := g.x0 + 1; x <= g.x1-1; x++ {
+			if y == g.y0+1 || y == g.y1-1 || x == g.x0+1 || x == g.x1-1 {
+				rows[y][x] = '#'
+			} else {
+				rows[y][x] = 'H'
+			}
+		}
+	}
+	rows[g.y0+1][cx] = '-'
+
+	for _, y := range opts.TunnelRows {
+		rows[y][0], rows[y][g.w-1] = 'T', 'T'
+		rows[y][1], rows[y][g.w-2] = ' ', ' '
+	}
+
+	if err := g.placePellets(rows, opts.PowerPellets); err != nil {
+		return nil, err
+	}
+
+	mid := g.y0 + 3
+	rows[pac.Y][pac.X] = 'C'
+	rows[g.y0][cx] = 'B'
+	rows[mid][cx] = 'P'
+	rows[mid][cx-2] = 'I'
+	rows[mid][cx+2] = 'Y'
+	rows[g.y1][cx] = 'F'
+
+	// Scatter targets sit on the outer corners, as in the arcade.
+	rows[0][g.w-1], rows[0][0] = 'b', 'p'
+	rows[g.h-1][g.w-1], rows[g.h-1][0] = 'i', 'y'
+
+	var sb strings.Builder
+	for _, r := range rows {
+		sb.WriteString(string(r))
+		sb.WriteByte('\n')
+	}
+	m, err := Parse(strings.NewReader(sb.String()))
+	if err != nil {
+		panic("maze: generator produced unparsable layout: " + err.Error())
+	}
+	return m, nil
+}
+
+// placePellets puts power pellets in mirrored pairs, starting with the
+// corners and continuing with random dot tiles in the left half. It fails
+// if there are not enough dot tiles for count pellets.
+func (g *generator) placePellets(rows [][]rune, count int) error {
+	candidates := []Point{{X: 1, Y: 1}, {X: 1, Y: g.h - 2}}
+	var rest []Point
+	for y := 1; y < g.h-1; y += 2 {
+		for x := 1; x < g.w/2; x += 2 {
+			rest = append(rest, Point{X: x, Y: y})
+		}
+	}
+	g.rng.Shuffle(len(rest), func(i, j int) { rest[i], rest[j] = rest[j], rest[i] })
+	candidates = append(candidates, rest...)
+
+	placed := 0
+	for _, p := range candidates {
+		if placed == count {
+			return nil
+		}
+		if rows[p.Y][p.X] != '.' {
+			continue
+		}
+		q := g.mirror(p)
+		rows[p.Y][p.X], rows[q.Y][q.X] = 'o', 'o'
+		placed += 2
+	}
+	if placed < count {
+		return fmt.Errorf("power pellet count %d: only %d fit in a %dx%d maze", count, placed, g.w, g.h)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package maze
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// genSizes lists the maze sizes the generator tests run on.
+var genSizes = [][2]int{{15, 13}, {21, 17}, {27, 23}, {31, 31}}
+
+func TestGenerateIsDeterministic(t *testing.T) {
+	for _, size := range genSizes {
+		w, h := size[0], size[1]
+		a, err := Generate(7, w, h, DefaultGenOptions(h))
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := Generate(7, w, h, DefaultGenOptions(h))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ga, gb := glyphs(a), glyphs(b); ga != gb {
+			t.Errorf("%dx%d: same seed gave different mazes:\n%s\n%s", w, h, ga, gb)
+		}
+	}
+}
+
+func TestGenerateIsValid(t *testing.T) {
+	for _, size := range genSizes {
+		w, h := size[0], size[1]
+		// Besides the defaults, try two tunnels, more pellets and no extra
+		// loops, which leaves removing dead ends to braid alone.
+		for i, opts := range []GenOptions{
+			DefaultGenOptions(h),
+			{TunnelRows: []int{3, h - 4}, PowerPellets: 8},
+		} {
+			for seed := int64(1); seed <= 25; seed++ {
+				m, err := Generate(seed, w, h, opts)
+				if err != nil {
+					t.Fatalf("%dx%d options %d seed %d: %v", w, h, i, seed, err)
+				}
+				if m.Width() != w || m.Height() != h {
+					t.Errorf("%dx%d options %d seed %d: got %dx%d", w, h, i, seed, m.Width(), m.Height())
+				}
+				// Warnings count too: a generated maze has no dead ends or
+				// other quirks.
+				for _, p := range Validate(m) {
+					t.Errorf("%dx%d options %d seed %d: %v", w, h, i, seed, p)
+				}
+				if got := countTiles(m, PowerPellet); got != opts.PowerPellets {
+					t.Errorf("%dx%d options %d seed %d: %d power pellets, want %d", w, h, i, seed, got, opts.PowerPellets)
+				}
+			}
+		}
+	}
+}
+
+func TestGenerateIsMirrored(t *testing.T) {
+	for _, size := range genSizes {
+		w, h := size[0], size[1]
+		for seed := int64(1); seed <= 10; seed++ {
+			m, err := Generate(seed, w, h, DefaultGenOptions(h))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for y := 0; y < h; y++ {
+				for x := 0; x < w/2; x++ {
+					l, _ := m.TileAt(x, y)
+					r, _ := m.TileAt(w-1-x, y)
+					if l != r {
+						t.Fatalf("%dx%d seed %d: tile (%d,%d) is %v but its mirror is %v", w, h, seed, x, y, l, r)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestGenerateRejectsBadSizes(t *testing.T) {
+	for _, size := range [][2]int{{14, 13}, {15, 12}, {13, 13}, {15, 11}} {
+		if _, err := Generate(1, size[0], size[1], DefaultGenOptions(size[1])); err == nil {
+			t.Errorf("%dx%d: Generate succeeded, want an error", size[0], size[1])
+		}
+	}
+}
+
+func TestGenerateRejectsBadOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		opts GenOptions
+		want string
+	}{
+		{"odd pellets", GenOptions{PowerPellets: 3}, "want an even number"},
+		{"negative pellets", GenOptions{PowerPellets: -2}, "want an even number"},
+		{"too many pellets", GenOptions{PowerPellets: 200}, "only"},
+		{"even tunnel row", GenOptions{TunnelRows: []int{4}}, "tunnel row 4"},
+		{"tunnel row on the edge", GenOptions{TunnelRows: []int{12}}, "tunnel row 12"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Generate(1, 15, 13, tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Generate error %v, want one containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// glyphs describes a maze's tiles, spawns and scatter targets for
+// comparison.
+func glyphs(m *Maze) string {
+	s := fmt.Sprint(m.grid)
+	for _, name := range requiredSpawns {
+		p, _ := m.Spawn(name)
+		q, _ := m.ScatterTarget(name)
+		s += fmt.Sprintf(" %s=%v/%v", name, p, q)
+	}
+	return s
+}
+
+// countTiles returns how many tiles of m are t.
+func countTiles(m *Maze, t Tile) int {
+	n := 0
+	for _, row := range m.grid {
+		for _, tile := range row {
+			if tile == t {
+				n++
+			}
+		}
+	}
+	return n
+}
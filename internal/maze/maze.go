@@ -2,8 +2,6 @@ package maze
 
 import (
 	"errors"
-	"strconv"
-	"strings"
 )
 
 // Tile represents a type of cell in the maze.
@@ -14,13 +12,40 @@ const (
 	Dot
 	Empty
 	PowerPellet
+	// Door is the ghost house door. Only ghosts entering or leaving the
+	// house may pass it.
+	Door
 )
 
+// Walkable reports whether Pac-Man and roaming ghosts may enter the tile.
+func (t Tile) Walkable() bool {
+	return t != Wall && t != Door
+}
+
+// Point is a tile coordinate in the maze.
+type Point struct {
+	X, Y int
+}
+
 // Maze represents the layout of the game field.
 type Maze struct {
-	width  int
-	height int
-	grid   [][]Tile
+	width    int
+	height   int
+	grid     [][]Tile
+	spawns   map[string]Point
+	scatter  map[string]Point
+	house    []Point
+	inHouse  map[Point]bool
+	fruit    Point
+	hasFruit bool
+	tunnels  map[Point]bool
+	// portals maps portal entrances to their exits; portalGlyphs keeps the
+	// digit of every portal marker.
+	portals      map[Point]Point
+	portalGlyphs map[Point]rune
+	// memo caches data derived from the layout, such as distance maps.
+	// Eating dots keeps it valid; any other tile change clears it.
+	memo map[any]any
 }
 
 // Width returns the width of the maze.
@@ -47,6 +72,7 @@ func (m *Maze) SetTile(x, y int, tile Tile) error {
 		return errors.New("out of bounds")
 	}
 	m.grid[y][x] = tile
+	m.memo = nil
 	return nil
 }
 
@@ -59,58 +85,208 @@ func (m *Maze) EatItem(x, y int) Tile {
 	return tile
 }
 
-// LoadDefault returns a static hardcoded maze for testing/demo.
+// LoadDefault returns the built-in default maze.
 func LoadDefault() *Maze {
-	layout := []string{
-		"####################",
-		"#........##........#",
-		"#.####.#.##.####.#.#",
-		" o#  #.#.##.#  #.#o ",
-		"#.####.#.##.####.#.#",
-		"#..................#",
-		"####################",
-	}
-
-	// Sanity check: tunnel sides must be symmetric (either both open or both closed)
-	for y, row := range layout {
-		if len(row) != len(layout[0]) {
-			panic("invalid maze: inconsistent row widths")
+	m, err := LoadBuiltin("default")
+	if err != nil {
+		panic("invalid built-in maze: " + err.Error())
+	}
+	return m
+}
+
+// Clone returns an independent copy of the maze.
+func (m *Maze) Clone() *Maze {
+	c := *m
+	c.memo = nil
+	c.grid = make([][]Tile, len(m.grid))
+	for y, row := range m.grid {
+		c.grid[y] = append([]Tile(nil), row...)
+	}
+	c.house = append([]Point(nil), m.house...)
+	c.inHouse = make(map[Point]bool, len(m.inHouse))
+	for p := range m.inHouse {
+		c.inHouse[p] = true
+	}
+	c.spawns = make(map[string]Point, len(m.spawns))
+	for name, p := range m.spawns {
+		c.spawns[name] = p
+	}
+	c.scatter = make(map[string]Point, len(m.scatter))
+	for name, p := range m.scatter {
+		c.scatter[name] = p
+	}
+	c.tunnels = make(map[Point]bool, len(m.tunnels))
+	for p := range m.tunnels {
+		c.tunnels[p] = true
+	}
+	c.portals = make(map[Point]Point, len(m.portals))
+	for p, q := range m.portals {
+		c.portals[p] = q
+	}
+	c.portalGlyphs = make(map[Point]rune, len(m.portalGlyphs))
+	for p, g := range m.portalGlyphs {
+		c.portalGlyphs[p] = g
+	}
+	return &c
+}
+
+// Spawn returns the named spawn point, if the maze defines it.
+func (m *Maze) Spawn(name string) (Point, bool) {
+	p, ok := m.spawns[name]
+	return p, ok
+}
+
+// ScatterTarget returns the tile the named ghost heads for while
+// scattering, if the maze marks one.
+func (m *Maze) ScatterTarget(name string) (Point, bool) {
+	p, ok := m.scatter[name]
+	return p, ok
+}
+
+// GhostHouse returns the floor tiles of the ghost house.
+func (m *Maze) GhostHouse() []Point {
+	return m.house
+}
+
+// InHouse reports whether (x, y) is ghost house floor.
+func (m *Maze) InHouse(x, y int) bool {
+	return m.inHouse[Point{X: x, Y: y}]
+}
+
+// Door returns the ghost house door, if the maze has one.
+func (m *Maze) Door() (Point, bool) {
+	for y := 0; y < m.height; y++ {
+		for x := 0; x < m.width; x++ {
+			if m.grid[y][x] == Door {
+				return Point{X: x, Y: y}, true
+			}
+		}
+	}
+	return Point{}, false
+}
+
+// HouseExit returns the walkable tile just outside the ghost house door.
+func (m *Maze) HouseExit() (Point, bool) {
+	door, ok := m.Door()
+	if !ok {
+		return Point{}, false
+	}
+	for _, q := range m.neighbors(door, false) {
+		if !m.inHouse[q] {
+			return q, true
 		}
-		left := row[0]
-		right := row[len(row)-1]
-		if (left == ' ' && right == '#') || (left == '#' && right == ' ') {
-			panic("invalid maze: asymmetric tunnel on row " + strconv.Itoa(y))
+	}
+	return Point{}, false
+}
+
+// HouseCenter returns the ghost house floor tile closest to the middle of
+// the house, where eaten ghosts are revived. Ties go to the topmost, then
+// leftmost tile.
+func (m *Maze) HouseCenter() (Point, bool) {
+	if len(m.house) == 0 {
+		return Point{}, false
+	}
+	var sx, sy int
+	for _, p := range m.house {
+		sx += p.X
+		sy += p.Y
+	}
+	n := len(m.house)
+	best := m.house[0]
+	bestDist := -1
+	for _, p := range m.house {
+		dx, dy := p.X*n-sx, p.Y*n-sy
+		d := dx*dx + dy*dy
+		if bestDist < 0 || d < bestDist || d == bestDist && (p.Y < best.Y || p.Y == best.Y && p.X < best.X) {
+			best, bestDist = p, d
 		}
 	}
+	return best, true
+}
 
-	height := len(layout)
-	width := len(layout[0])
-	grid := make([][]Tile, height)
-
-	for y, line := range layout {
-		grid[y] = make([]Tile, width)
-		for x, char := range strings.Split(line, "") {
-			switch char {
-			case "#":
-				grid[y][x] = Wall
-			case ".":
-				grid[y][x] = Dot
-			case "o":
-				grid[y][x] = PowerPellet
-			default:
-				grid[y][x] = Empty
-			}
+// FruitSpot returns the tile where bonus fruit appears, if defined.
+func (m *Maze) FruitSpot() (Point, bool) {
+	return m.fruit, m.hasFruit
+}
+
+// IsTunnel reports whether the tile at (x, y) is marked as tunnel floor.
+func (m *Maze) IsTunnel(x, y int) bool {
+	return m.tunnels[Point{X: x, Y: y}]
+}
+
+// Wrap maps a point just off the edge of a tunnel row or column to the
+// opposite side of the maze. Other points are returned unchanged.
+func (m *Maze) Wrap(p Point) Point {
+	if m.IsTunnelRow(p.Y) {
+		p.X = (p.X + m.width) % m.width
+	}
+	if m.IsTunnelColumn(p.X) {
+		p.Y = (p.Y + m.height) % m.height
+	}
+	return p
+}
+
+// Step returns the tile an entity at p arrives on when it moves by the unit
+// offset d: off-edge moves wrap through tunnels and moving onto a portal
+// entrance lands on its exit. It does not check that the tile is open.
+func (m *Maze) Step(p, d Point) Point {
+	q := m.Wrap(Point{X: p.X + d.X, Y: p.Y + d.Y})
+	if exit, ok := m.portals[q]; ok {
+		return exit
+	}
+	return q
+}
+
+// Portal returns the exit of the portal entered at p, if p is an entrance.
+func (m *Maze) Portal(p Point) (Point, bool) {
+	exit, ok := m.portals[p]
+	return exit, ok
+}
+
+// PortalsTo returns the portal entrances that lead to p.
+func (m *Maze) PortalsTo(p Point) []Point {
+	var out []Point
+	for in, exit := range m.portals {
+		if exit == p {
+			out = append(out, in)
 		}
 	}
+	return out
+}
 
-	return &Maze{
-		width:  width,
-		height: height,
-		grid:   grid,
+// HasPortals reports whether the maze has any portals.
+func (m *Maze) HasPortals() bool {
+	return len(m.portals) > 0
+}
+
+// PortalGlyph returns the digit marking the portal tile at p, which may be
+// an entrance or the exit of a one-way portal.
+func (m *Maze) PortalGlyph(p Point) (rune, bool) {
+	g, ok := m.portalGlyphs[p]
+	return g, ok
+}
+
+// Memo returns the value cached under key, calling build to compute it on
+// first use. Clones start with an empty cache.
+func (m *Maze) Memo(key any, build func() any) any {
+	if v, ok := m.memo[key]; ok {
+		return v
 	}
+	v := build()
+	if m.memo == nil {
+		m.memo = map[any]any{}
+	}
+	m.memo[key] = v
+	return v
 }
 
 // IsTunnelRow returns true if row y has open sides (tunnel).
 func (m *Maze) IsTunnelRow(y int) bool {
 	return y >= 0 && y < m.height && m.grid[y][0] != Wall && m.grid[y][m.width-1] != Wall
 }
+
+// IsTunnelColumn reports whether column x is open at the top and bottom
+// edges and wraps vertically.
+func (m *Maze) IsTunnelColumn(x int) bool {
+	return x >= 0 && x < m.width && m.grid[0][x] != Wall && m.grid[m.height-1][x] != Wall
+}
@@ -0,0 +1,32 @@
+package maze
+
+import (
+	"strings"
+	"testing"
+)
+
+// houseMaze has a house four tiles wide, so two tiles tie for its center.
+const houseMaze = `####################
+#C.................#
+#.######.##.######.#
+#.######B..I######.#
+#.########-#######.#
+#......#HPYH#......#
+#.####.######.####.#
+#..................#
+####################
+`
+
+func TestHouseCenterIsStable(t *testing.T) {
+	want := Point{X: 9, Y: 5}
+	for i := 0; i < 50; i++ {
+		m, err := Parse(strings.NewReader(houseMaze))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, ok := m.HouseCenter()
+		if !ok || got != want {
+			t.Fatalf("parse %d: HouseCenter() = %v, %v; want %v", i, got, ok, want)
+		}
+	}
+}
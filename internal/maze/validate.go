@@ -0,0 +1,268 @@
+package maze
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Severity tells whether a problem makes a maze unplayable.
+type Severity int
+
+const (
+	Warning Severity = iota
+	Error
+)
+
+func (s Severity) String() string {
+	if s == Error {
+		return "error"
+	}
+	return "warning"
+}
+
+// ProblemKind identifies the type of a maze problem.
+type ProblemKind int
+
+const (
+	MissingSpawn ProblemKind = iota
+	SpawnInWall
+	UnreachableDots
+	UnreachableHouse
+	DisconnectedRegion
+	DeadEnds
+	TunnelMismatch
+	MissingFruitSpot
+)
+
+func (k ProblemKind) String() string {
+	switch k {
+	case MissingSpawn:
+		return "missing spawn"
+	case SpawnInWall:
+		return "spawn in wall"
+	case UnreachableDots:
+		return "unreachable dots"
+	case UnreachableHouse:
+		return "unreachable ghost house"
+	case DisconnectedRegion:
+		return "disconnected region"
+	case DeadEnds:
+		return "dead ends"
+	case TunnelMismatch:
+		return "tunnel mismatch"
+	case MissingFruitSpot:
+		return "missing fruit spot"
+	default:
+		return "unknown problem"
+	}
+}
+
+// Problem is a single finding of Validate.
+type Problem struct {
+	Kind     ProblemKind
+	Severity Severity
+	// Points lists the tiles involved, if any.
+	Points []Point
+	Detail string
+}
+
+// String formats the problem for display.
+func (p Problem) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s: %s: %s", p.Severity, p.Kind, p.Detail)
+	const maxShown = 8
+	for i, pt := range p.Points {
+		if i == maxShown {
+			fmt.Fprintf(&sb, " … (%d more)", len(p.Points)-maxShown)
+			break
+		}
+		fmt.Fprintf(&sb, " (%d,%d)", pt.X, pt.Y)
+	}
+	return sb.String()
+}
+
+// HasErrors reports whether any of the problems is an error.
+func HasErrors(problems []Problem) bool {
+	for _, p := range problems {
+		if p.Severity == Error {
+			return true
+		}
+	}
+	return false
+}
+
+// requiredSpawns lists the spawn points every playable maze must define.
+var requiredSpawns = []string{SpawnPacman, SpawnBlinky, SpawnInky, SpawnPinky, SpawnClyde}
+
+// Validate checks a maze for problems that make it unplayable (errors) or
+// unusual (warnings). A nil result means the maze is fine.
+func Validate(m *Maze) []Problem {
+	var problems []Problem
+	add := func(kind ProblemKind, sev Severity, pts []Point, format string, args ...any) {
+		problems = append(problems, Problem{
+			Kind:     kind,
+			Severity: sev,
+			Points:   pts,
+			Detail:   fmt.Sprintf(format, args...),
+		})
+	}
+
+	for y := 0; y < m.height; y++ {
+		if (m.grid[y][0] == Wall) != (m.grid[y][m.width-1] == Wall) {
+			add(TunnelMismatch, Error, []Point{{X: 0, Y: y}, {X: m.width - 1, Y: y}},
+				"row %d is open on one side only", y)
+		}
+	}
+	for p := range m.tunnels {
+		if m.grid[p.Y][p.X] == Wall {
+			add(TunnelMismatch, Error, []Point{p}, "tunnel marker on a wall")
+		}
+	}
+
+	for _, name := range requiredSpawns {
+		p, ok := m.spawns[name]
+		switch {
+		case !ok:
+			add(MissingSpawn, Error, nil, "no %s spawn", name)
+		case !m.inBounds(p):
+			add(SpawnInWall, Error, []Point{p}, "%s spawn is outside the maze", name)
+		case m.grid[p.Y][p.X] == Wall:
+			add(SpawnInWall, Error, []Point{p}, "%s spawn is on a wall", name)
+		}
+	}
+
+	if !m.hasFruit {
+		add(MissingFruitSpot, Warning, nil, "no F marker, so bonus fruit never appears")
+	}
+
+	if start, ok := m.spawns[SpawnPacman]; ok && m.inBounds(start) && m.grid[start.Y][start.X] != Wall {
+		reached := m.flood(start, false)
+		var dots, house []Point
+		m.each(func(p Point, t Tile) {
+			if (t == Dot || t == PowerPellet) && !reached[p] {
+				dots = append(dots, p)
+			}
+		})
+		if len(dots) > 0 {
+			add(UnreachableDots, Error, dots, "%d dots cannot be reached from the Pac-Man spawn", len(dots))
+		}
+		// Ghosts reach the house through its door.
+		reached = m.flood(start, true)
+		for _, p := range m.house {
+			if !reached[p] {
+				house = append(house, p)
+			}
+		}
+		if len(house) > 0 {
+			add(UnreachableHouse, Error, house, "ghost house cannot be reached from the Pac-Man spawn")
+		}
+	}
+
+	regions := m.regions()
+	if len(regions) > 1 {
+		// The largest region is the playfield; report the others.
+		sort.Slice(regions, func(i, j int) bool { return len(regions[i]) > len(regions[j]) })
+		for _, r := range regions[1:] {
+			add(DisconnectedRegion, Warning, r, "%d open tiles are cut off from the rest of the maze", len(r))
+		}
+	}
+
+	var dead []Point
+	m.each(func(p Point, t Tile) {
+		if t != Wall && len(m.neighbors(p, true)) == 1 {
+			dead = append(dead, p)
+		}
+	})
+	if len(dead) > 0 {
+		add(DeadEnds, Warning, dead, "%d dead ends", len(dead))
+	}
+
+	return problems
+}
+
+// each calls fn for every tile in row-major order.
+func (m *Maze) each(fn func(Point, Tile)) {
+	for y := 0; y < m.height; y++ {
+		for x := 0; x < m.width; x++ {
+			fn(Point{X: x, Y: y}, m.grid[y][x])
+		}
+	}
+}
+
+func (m *Maze) inBounds(p Point) bool {
+	return p.X >= 0 && p.X < m.width && p.Y >= 0 && p.Y < m.height
+}
+
+// neighbors returns the open tiles reachable in one step from p, wrapping
+// through tunnels and portals. Doors count as open only when throughDoors is set.
+func (m *Maze) neighbors(p Point, throughDoors bool) []Point {
+	var out []Point
+	for _, d := range []Point{{X: 0, Y: -1}, {X: -1, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 0}} {
+		q := m.Step(p, d)
+		if !m.inBounds(q) {
+			continue
+		}
+		if t := m.grid[q.Y][q.X]; t.Walkable() || throughDoors && t == Door {
+			out = append(out, q)
+		}
+	}
+	return out
+}
+
+// flood returns the set of open tiles reachable from start.
+func (m *Maze) flood(start Point, throughDoors bool) map[Point]bool {
+	seen := map[Point]bool{start: true}
+	queue := []Point{start}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, next := range m.neighbors(cur, throughDoors) {
+			if !seen[next] {
+				seen[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return seen
+}
+
+// regions partitions the open tiles into connected components, ignoring
+// the direction of one-way portals.
+func (m *Maze) regions() [][]Point {
+	links := map[Point][]Point{}
+	m.each(func(p Point, t Tile) {
+		if t == Wall {
+			return
+		}
+		for _, q := range m.neighbors(p, true) {
+			links[p] = append(links[p], q)
+			links[q] = append(links[q], p)
+		}
+	})
+	seen := map[Point]bool{}
+	var out [][]Point
+	m.each(func(p Point, t Tile) {
+		if t == Wall || seen[p] {
+			return
+		}
+		seen[p] = true
+		region := []Point{p}
+		for i := 0; i < len(region); i++ {
+			for _, q := range links[region[i]] {
+				if !seen[q] {
+					seen[q] = true
+					region = append(region, q)
+				}
+			}
+		}
+		sort.Slice(region, func(i, j int) bool {
+			if region[i].Y != region[j].Y {
+				return region[i].Y < region[j].Y
+			}
+			return region[i].X < region[j].X
+		})
+		out = append(out, region)
+	})
+	return out
+}
@@ -0,0 +1,121 @@
+package maze
+
+import (
+	"strings"
+	"testing"
+)
+
+// validMaze is a small loop with every required spawn and no problems.
+const validMaze = `#########
+#C.....B#
+#.#####.#
+#I..P.FY#
+#########
+`
+
+func mustParse(t *testing.T, s string) *Maze {
+	t.Helper()
+	m, err := Parse(strings.NewReader(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestValidateCleanMazes(t *testing.T) {
+	if problems := Validate(mustParse(t, validMaze)); len(problems) != 0 {
+		t.Errorf("small maze: unexpected problems %v", problems)
+	}
+	if problems := Validate(LoadDefault()); len(problems) != 0 {
+		t.Errorf("default maze: unexpected problems %v", problems)
+	}
+}
+
+func TestValidateProblems(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		mutate   func(*Maze)
+		kind     ProblemKind
+		severity Severity
+	}{
+		{
+			name:     "missing spawn",
+			input:    strings.Replace(validMaze, "Y", ".", 1),
+			kind:     MissingSpawn,
+			severity: Error,
+		},
+		{
+			name:     "spawn in wall",
+			input:    validMaze,
+			mutate:   func(m *Maze) { m.SetTile(7, 1, Wall) },
+			kind:     SpawnInWall,
+			severity: Error,
+		},
+		{
+			name:     "unreachable dots",
+			input:    validMaze + "##.######\n#########\n",
+			kind:     UnreachableDots,
+			severity: Error,
+		},
+		{
+			name:     "unreachable house",
+			input:    validMaze + "##HH#####\n#########\n",
+			kind:     UnreachableHouse,
+			severity: Error,
+		},
+		{
+			name:     "tunnel open on one side",
+			input:    validMaze,
+			mutate:   func(m *Maze) { m.SetTile(0, 1, Empty) },
+			kind:     TunnelMismatch,
+			severity: Error,
+		},
+		{
+			name:     "tunnel marker on a wall",
+			input:    strings.Replace(validMaze, "#C.....B#", "#CT....B#", 1),
+			mutate:   func(m *Maze) { m.SetTile(2, 1, Wall) },
+			kind:     TunnelMismatch,
+			severity: Error,
+		},
+		{
+			name:     "disconnected region",
+			input:    validMaze + "## ######\n#########\n",
+			kind:     DisconnectedRegion,
+			severity: Warning,
+		},
+		{
+			name:     "missing fruit spot",
+			input:    strings.Replace(validMaze, "F", ".", 1),
+			kind:     MissingFruitSpot,
+			severity: Warning,
+		},
+		{
+			name:     "dead end",
+			input:    "#########\n#C.....B#\n#.#####.#\n#I..P..Y#\n####.####\n#########\n",
+			kind:     DeadEnds,
+			severity: Warning,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mustParse(t, tt.input)
+			if tt.mutate != nil {
+				tt.mutate(m)
+			}
+			problems := Validate(m)
+			for _, p := range problems {
+				if p.Kind == tt.kind {
+					if p.Severity != tt.severity {
+						t.Errorf("%s reported as %s, want %s", tt.kind, p.Severity, tt.severity)
+					}
+					if tt.severity == Error && !HasErrors(problems) {
+						t.Errorf("HasErrors = false with an error reported")
+					}
+					return
+				}
+			}
+			t.Errorf("no %s problem in %v", tt.kind, problems)
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package observe
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/vinser/pacmanai/internal/sim"
+)
+
+// Encoding turns observations into tensors of a fixed shape.
+type Encoding struct {
+	// Labels names the entries along the first axis of the tensors: the
+	// planes of a board encoding or the entries of the feature vector.
+	Labels []string
+	Encode func(sim.Observation) Tensor
+}
+
+// EncodingNames lists the encodings ParseEncoding accepts.
+var EncodingNames = []string{"planes", "window:R", "features"}
+
+// ParseEncoding returns the encoding named by spec: "planes" for the whole
+// board, "window:R" for the window of radius R around Pac-Man, or
+// "features" for the feature vector.
+func ParseEncoding(spec string) (Encoding, error) {
+	switch name, arg, hasArg := strings.Cut(spec, ":"); {
+	case spec == "planes":
+		return Encoding{Labels: PlaneNames(), Encode: Planes}, nil
+	case spec == "features":
+		return Encoding{Labels: FeatureNames(), Encode: Features}, nil
+	case name == "window" && hasArg:
+		radius, err := strconv.Atoi(arg)
+		if err != nil || radius < 0 {
+			return Encoding{}, fmt.Errorf("window radius must be a non-negative integer, got %q", arg)
+		}
+		return Encoding{
+			Labels: PlaneNames(),
+			Encode: func(obs sim.Observation) Tensor { return Window(obs, radius) },
+		}, nil
+	}
+	return Encoding{}, fmt.Errorf("unknown encoding %q (available: %s)", spec, strings.Join(EncodingNames, ", "))
+}
@@ -0,0 +1,105 @@
+package observe
+
+import (
+	"time"
+
+	"github.com/vinser/pacmanai/internal/entity"
+	"github.com/vinser/pacmanai/internal/maze"
+	"github.com/vinser/pacmanai/internal/pathfind"
+	"github.com/vinser/pacmanai/internal/sim"
+)
+
+// maxPowerLeft is the longest frightened time of the arcade, used to scale
+// the power_left feature.
+const maxPowerLeft = 6 * time.Second
+
+// featureDirections lists the moves the features describe in the order of
+// entity.Direction, which is also the order of sim's move actions.
+var featureDirections = []struct {
+	name   string
+	offset maze.Point
+}{
+	{"up", maze.Point{X: 0, Y: -1}},
+	{"down", maze.Point{X: 0, Y: 1}},
+	{"left", maze.Point{X: -1, Y: 0}},
+	{"right", maze.Point{X: 1, Y: 0}},
+}
+
+// FeatureNames returns the names of the Features entries in order.
+func FeatureNames() []string {
+	var names []string
+	for _, d := range featureDirections {
+		names = append(names, d.name+"_open", d.name+"_food", d.name+"_ghost", d.name+"_frightened")
+	}
+	return append(names, "power_left")
+}
+
+// Features encodes the situation around Pac-Man as a vector of values in
+// [0, 1]. For each direction in the order up, down, left, right it gives
+// whether Pac-Man can move that way and the path distances, starting with
+// that move, to the nearest dot or pellet, the nearest dangerous ghost and
+// the nearest frightened ghost. The i-th group of four entries thus belongs
+// to entity.Direction(i) and to the action sim.ActionFor of it. Distances
+// are divided by width+height and capped at 1, which also stands for none
+// in reach. The last entry is the frightened time left as a fraction of 6
+// seconds.
+func Features(obs sim.Observation) Tensor {
+	m := obs.Maze
+	g := pathfind.NewGraph(m, false)
+	scale := float32(m.Width() + m.Height())
+	norm := func(d int, ok bool) float32 {
+		if !ok {
+			return 1
+		}
+		return min(float32(d)/scale, 1)
+	}
+
+	var danger, frightened []maze.Point
+	for _, gh := range obs.Ghosts {
+		p := maze.Point{X: gh.Pos.X, Y: gh.Pos.Y}
+		switch gh.State {
+		case entity.Chase, entity.Scatter:
+			danger = append(danger, p)
+		case entity.Frightened:
+			frightened = append(frightened, p)
+		}
+	}
+	isFood := func(p maze.Point) bool {
+		t, _ := m.TileAt(p.X, p.Y)
+		return t == maze.Dot || t == maze.PowerPellet
+	}
+
+	pac := maze.Point{X: obs.Pacman.X, Y: obs.Pacman.Y}
+	t := NewTensor(len(featureDirections)*4 + 1)
+	for i, d := range featureDirections {
+		next, ok := g.Step(pac, d.offset)
+		if !ok {
+			t.Data[i*4+1], t.Data[i*4+2], t.Data[i*4+3] = 1, 1, 1
+			continue
+		}
+		// Paths start with the move and do not lead back through Pac-Man.
+		blocked := map[maze.Point]bool{pac: true}
+		dist := func(goal func(maze.Point) bool) float32 {
+			path, ok := g.Nearest(next, goal, blocked)
+			return norm(len(path)+1, ok)
+		}
+		t.Data[i*4] = 1
+		t.Data[i*4+1] = dist(isFood)
+		t.Data[i*4+2] = dist(among(danger))
+		t.Data[i*4+3] = dist(among(frightened))
+	}
+	t.Data[len(t.Data)-1] = min(float32(obs.PowerLeft)/float32(maxPowerLeft), 1)
+	return t
+}
+
+// among returns a predicate matching the given tiles.
+func among(pts []maze.Point) func(maze.Point) bool {
+	return func(p maze.Point) bool {
+		for _, q := range pts {
+			if p == q {
+				return true
+			}
+		}
+		return false
+	}
+}
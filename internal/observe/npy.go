@@ -0,0 +1,58 @@
+package observe
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// npyMagic starts every .npy file, followed by the format version 1.0.
+const npyMagic = "\x93NUMPY\x01\x00"
+
+// WriteNPY writes t in the NumPy .npy format as little-endian float32, so
+// numpy.load reads it back with its shape.
+func WriteNPY(w io.Writer, t Tensor) error {
+	dims := make([]string, len(t.Shape))
+	for i, d := range t.Shape {
+		dims[i] = strconv.Itoa(d)
+	}
+	shape := strings.Join(dims, ", ")
+	if len(dims) == 1 {
+		shape += ","
+	}
+	header := fmt.Sprintf("{'descr': '<f4', 'fortran_order': False, 'shape': (%s), }", shape)
+	// The header is padded with spaces and ends in a newline so that the
+	// data starts on a 64-byte boundary.
+	prefix := len(npyMagic) + 2
+	pad := 64 - (prefix+len(header)+1)%64
+	header += strings.Repeat(" ", pad%64) + "\n"
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString(npyMagic)
+	binary.Write(bw, binary.LittleEndian, uint16(len(header)))
+	bw.WriteString(header)
+	var buf [4]byte
+	for _, v := range t.Data {
+		binary.LittleEndian.PutUint32(buf[:], math.Float32bits(v))
+		bw.Write(buf[:])
+	}
+	return bw.Flush()
+}
+
+// SaveNPY writes t to a .npy file at path.
+func SaveNPY(path string, t Tensor) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := WriteNPY(f, t); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,85 @@
+package observe
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestWriteNPY(t *testing.T) {
+	// The shapes give headers of different lengths. The last one fills
+	// exactly 128 bytes and needs no padding before the newline.
+	shapes := [][]int{{3}, {2, 3}, {11, 21, 21}, {1000000, 1}, {1, 2, 3, 4, 5, 6, 7}, append([]int{10}, slices.Repeat([]int{1}, 20)...)}
+	for _, shape := range shapes {
+		t.Run(fmt.Sprint(shape), func(t *testing.T) {
+			tn := NewTensor(shape...)
+			for i := range tn.Data[:min(len(tn.Data), 100)] {
+				tn.Data[i] = float32(i) - 0.5
+			}
+			var buf bytes.Buffer
+			if err := WriteNPY(&buf, tn); err != nil {
+				t.Fatal(err)
+			}
+			b := buf.Bytes()
+			if !bytes.HasPrefix(b, []byte("\x93NUMPY\x01\x00")) {
+				t.Fatalf("file starts with %q, want the magic and version 1.0", b[:8])
+			}
+			n := int(binary.LittleEndian.Uint16(b[8:10]))
+			if (10+n)%64 != 0 {
+				t.Errorf("data starts at byte %d, want a multiple of 64", 10+n)
+			}
+			header := string(b[10 : 10+n])
+			if !strings.HasSuffix(header, "\n") {
+				t.Errorf("header %q does not end in a newline", header)
+			}
+			dict, pad, _ := strings.Cut(header, "}")
+			if strings.Trim(pad, " ") != "\n" {
+				t.Errorf("header padding %q, want spaces and a newline", pad)
+			}
+			dims := make([]string, len(shape))
+			for i, d := range shape {
+				dims[i] = fmt.Sprint(d)
+			}
+			want := "(" + strings.Join(dims, ", ") + ")"
+			if len(shape) == 1 {
+				want = fmt.Sprintf("(%d,)", shape[0])
+			}
+			if wantDict := "{'descr': '<f4', 'fortran_order': False, 'shape': " + want + ", "; dict != wantDict {
+				t.Errorf("header %q, want %q}", dict, wantDict)
+			}
+			data := b[10+n:]
+			if len(data) != 4*len(tn.Data) {
+				t.Fatalf("%d data bytes, want %d", len(data), 4*len(tn.Data))
+			}
+			for i, v := range tn.Data {
+				if got := math.Float32frombits(binary.LittleEndian.Uint32(data[4*i:])); got != v {
+					t.Fatalf("value %d = %v, want %v", i, got, v)
+				}
+			}
+		})
+	}
+}
+
+func TestSaveNPY(t *testing.T) {
+	tn := NewTensor(2, 2)
+	tn.Data[3] = 1
+	path := filepath.Join(t.TempDir(), "t.npy")
+	if err := SaveNPY(path, tn); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want bytes.Buffer
+	WriteNPY(&want, tn)
+	if !bytes.Equal(got, want.Bytes()) {
+		t.Errorf("file holds %q, want %q", got, want.Bytes())
+	}
+}
@@ -0,0 +1,153 @@
+// Package observe encodes game observations as numeric arrays for machine
+// learning: stacked tile planes of the whole board or of a window around
+// Pac-Man, and a compact feature vector. Arrays can be written as NumPy
+// .npy files.
+package observe
+
+import (
+	"github.com/vinser/pacmanai/internal/entity"
+	"github.com/vinser/pacmanai/internal/maze"
+	"github.com/vinser/pacmanai/internal/sim"
+)
+
+// Tensor is a dense array of float32 values in row-major order.
+type Tensor struct {
+	Shape []int     `json:"shape"`
+	Data  []float32 `json:"data"`
+}
+
+// NewTensor returns a zero tensor of the given shape.
+func NewTensor(shape ...int) Tensor {
+	n := 1
+	for _, d := range shape {
+		n *= d
+	}
+	return Tensor{Shape: shape, Data: make([]float32, n)}
+}
+
+// Flat returns the values in row-major order. The slice is shared with
+// the tensor.
+func (t Tensor) Flat() []float32 {
+	return t.Data
+}
+
+// Plane is one channel of the board encoding.
+type Plane int
+
+const (
+	// PlaneWalls marks walls and the ghost house door, which Pac-Man
+	// cannot pass. Tiles outside the maze count as walls.
+	PlaneWalls Plane = iota
+	PlaneDots
+	PlanePellets
+	PlanePacman
+	// PlaneBlinky through PlaneClyde mark each ghost whatever its state.
+	PlaneBlinky
+	PlaneInky
+	PlanePinky
+	PlaneClyde
+	// PlaneFrightened marks ghosts that Pac-Man can eat.
+	PlaneFrightened
+	// PlaneEaten marks the eyes of eaten ghosts returning home.
+	PlaneEaten
+	PlaneFruit
+	// PlaneTunnels marks tunnel floor, where ghosts slow down, and
+	// PlanePortals marks portal tiles. Where a portal leads is not encoded.
+	PlaneTunnels
+	PlanePortals
+	// NumPlanes is the number of channels.
+	NumPlanes
+)
+
+var planeNames = []string{"walls", "dots", "pellets", "pacman", "blinky", "inky", "pinky", "clyde", "frightened", "eaten", "fruit", "tunnels", "portals"}
+
+// String returns the lowercase name of the plane.
+func (p Plane) String() string {
+	if p >= 0 && int(p) < len(planeNames) {
+		return planeNames[p]
+	}
+	return "plane"
+}
+
+// PlaneNames returns the names of the planes in order.
+func PlaneNames() []string {
+	return append([]string(nil), planeNames...)
+}
+
+// ghostPlanes maps ghost types to their planes.
+var ghostPlanes = map[entity.GhostType]Plane{
+	entity.Blinky: PlaneBlinky,
+	entity.Inky:   PlaneInky,
+	entity.Pinky:  PlanePinky,
+	entity.Clyde:  PlaneClyde,
+}
+
+// Planes encodes the board as a tensor of shape [NumPlanes, height, width]
+// with a 1 wherever a plane's feature is present.
+func Planes(obs sim.Observation) Tensor {
+	m := obs.Maze
+	w, h := m.Width(), m.Height()
+	t := NewTensor(int(NumPlanes), h, w)
+	set := func(p Plane, x, y int) {
+		if x >= 0 && x < w && y >= 0 && y < h {
+			t.Data[(int(p)*h+y)*w+x] = 1
+		}
+	}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if _, ok := m.PortalGlyph(maze.Point{X: x, Y: y}); ok {
+				set(PlanePortals, x, y)
+			}
+			if m.IsTunnel(x, y) {
+				set(PlaneTunnels, x, y)
+			}
+			tile, _ := m.TileAt(x, y)
+			switch tile {
+			case maze.Wall, maze.Door:
+				set(PlaneWalls, x, y)
+			case maze.Dot:
+				set(PlaneDots, x, y)
+			case maze.PowerPellet:
+				set(PlanePellets, x, y)
+			}
+		}
+	}
+	set(PlanePacman, obs.Pacman.X, obs.Pacman.Y)
+	for _, g := range obs.Ghosts {
+		set(ghostPlanes[g.Type], g.Pos.X, g.Pos.Y)
+		switch g.State {
+		case entity.Frightened:
+			set(PlaneFrightened, g.Pos.X, g.Pos.Y)
+		case entity.Eaten:
+			set(PlaneEaten, g.Pos.X, g.Pos.Y)
+		}
+	}
+	if obs.Fruit != nil {
+		set(PlaneFruit, obs.Fruit.Pos().X, obs.Fruit.Pos().Y)
+	}
+	return t
+}
+
+// Window encodes the square of side 2*radius+1 centered on Pac-Man as a
+// tensor of shape [NumPlanes, side, side]. The window wraps through
+// tunnels like the game does; elsewhere tiles beyond the edge are walls.
+func Window(obs sim.Observation, radius int) Tensor {
+	m := obs.Maze
+	w, h := m.Width(), m.Height()
+	full := Planes(obs)
+	side := 2*radius + 1
+	t := NewTensor(int(NumPlanes), side, side)
+	for wy := 0; wy < side; wy++ {
+		for wx := 0; wx < side; wx++ {
+			p := m.Wrap(maze.Point{X: obs.Pacman.X + wx - radius, Y: obs.Pacman.Y + wy - radius})
+			if p.X < 0 || p.X >= w || p.Y < 0 || p.Y >= h {
+				t.Data[(int(PlaneWalls)*side+wy)*side+wx] = 1
+				continue
+			}
+			for c := 0; c < int(NumPlanes); c++ {
+				t.Data[(c*side+wy)*side+wx] = full.Data[(c*h+p.Y)*w+p.X]
+			}
+		}
+	}
+	return t
+}
@@ -0,0 +1,216 @@
+package observe
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vinser/pacmanai/internal/entity"
+	"github.com/vinser/pacmanai/internal/maze"
+	"github.com/vinser/pacmanai/internal/sim"
+)
+
+// testMaze is 7x5 with a tunnel through row 2.
+const testMaze = `#######
+#o.C..#
+T.#.#.T
+#.....#
+#######
+`
+
+// testObservation places Pac-Man at (x, y) with Blinky chasing from the
+// tunnel mouth, a frightened Inky, the eyes of Pinky and a fruit.
+func testObservation(t *testing.T, x, y int) sim.Observation {
+	t.Helper()
+	m, err := maze.Parse(strings.NewReader(testMaze))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sim.Observation{
+		Maze:      m,
+		Pacman:    entity.Position{X: x, Y: y},
+		PowerLeft: 3 * time.Second,
+		Ghosts: []entity.GhostView{
+			{Type: entity.Blinky, Pos: entity.Position{X: 6, Y: 2}, State: entity.Chase},
+			{Type: entity.Inky, Pos: entity.Position{X: 1, Y: 3}, State: entity.Frightened},
+			{Type: entity.Pinky, Pos: entity.Position{X: 5, Y: 3}, State: entity.Eaten},
+		},
+		Fruit: entity.NewFruit(entity.Cherry, 100, entity.Position{X: 4, Y: 1}),
+	}
+}
+
+// planesAt returns the planes set at (x, y) of a [NumPlanes, h, w] tensor.
+func planesAt(tn Tensor, x, y int) []Plane {
+	h, w := tn.Shape[1], tn.Shape[2]
+	var out []Plane
+	for p := Plane(0); p < NumPlanes; p++ {
+		if tn.Data[(int(p)*h+y)*w+x] != 0 {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func TestPlanes(t *testing.T) {
+	obs := testObservation(t, 3, 1)
+	tn := Planes(obs)
+	if !slices.Equal(tn.Shape, []int{int(NumPlanes), 5, 7}) {
+		t.Fatalf("shape = %v, want [%d 5 7]", tn.Shape, NumPlanes)
+	}
+	if len(tn.Data) != int(NumPlanes)*5*7 {
+		t.Fatalf("%d values, want %d", len(tn.Data), int(NumPlanes)*5*7)
+	}
+	tests := []struct {
+		x, y int
+		want []Plane
+	}{
+		{0, 0, []Plane{PlaneWalls}},
+		{1, 1, []Plane{PlanePellets}},
+		{2, 1, []Plane{PlaneDots}},
+		{3, 1, []Plane{PlanePacman}},
+		{4, 1, []Plane{PlaneDots, PlaneFruit}},
+		{0, 2, []Plane{PlaneTunnels}},
+		{6, 2, []Plane{PlaneBlinky, PlaneTunnels}},
+		{1, 3, []Plane{PlaneDots, PlaneInky, PlaneFrightened}},
+		{5, 3, []Plane{PlaneDots, PlanePinky, PlaneEaten}},
+	}
+	for _, tt := range tests {
+		if got := planesAt(tn, tt.x, tt.y); !slices.Equal(got, tt.want) {
+			t.Errorf("(%d,%d): planes %v, want %v", tt.x, tt.y, got, tt.want)
+		}
+	}
+
+	m, err := maze.Parse(strings.NewReader("######\n#1.C1#\n######\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tn = Planes(sim.Observation{Maze: m, Pacman: entity.Position{X: 3, Y: 1}})
+	for x, want := range [][]Plane{{PlaneWalls}, {PlanePortals}, {PlaneDots}, {PlanePacman}, {PlanePortals}, {PlaneWalls}} {
+		if got := planesAt(tn, x, 1); !slices.Equal(got, want) {
+			t.Errorf("portal maze (%d,1): planes %v, want %v", x, got, want)
+		}
+	}
+
+	if names := PlaneNames(); len(names) != int(NumPlanes) || names[PlaneFruit] != PlaneFruit.String() {
+		t.Errorf("PlaneNames() = %v", names)
+	}
+}
+
+func TestWindow(t *testing.T) {
+	full := Planes(testObservation(t, 3, 1))
+
+	t.Run("edges", func(t *testing.T) {
+		tn := Window(testObservation(t, 3, 1), 2)
+		if !slices.Equal(tn.Shape, []int{int(NumPlanes), 5, 5}) {
+			t.Fatalf("shape = %v, want [%d 5 5]", tn.Shape, NumPlanes)
+		}
+		for wy := 0; wy < 5; wy++ {
+			for wx := 0; wx < 5; wx++ {
+				x, y := 3+wx-2, 1+wy-2
+				want := []Plane{PlaneWalls}
+				if y >= 0 {
+					want = planesAt(full, x, y)
+				}
+				if got := planesAt(tn, wx, wy); !slices.Equal(got, want) {
+					t.Errorf("window (%d,%d) = maze (%d,%d): planes %v, want %v", wx, wy, x, y, got, want)
+				}
+			}
+		}
+	})
+
+	t.Run("tunnel", func(t *testing.T) {
+		tn := Window(testObservation(t, 0, 2), 1)
+		tests := []struct {
+			wx, wy int
+			want   []Plane
+		}{
+			// Only the tunnel row wraps; the rows above and below end at
+			// the edge of the maze.
+			{0, 0, []Plane{PlaneWalls}},
+			{0, 1, []Plane{PlaneBlinky, PlaneTunnels}},
+			{0, 2, []Plane{PlaneWalls}},
+			{1, 0, []Plane{PlaneWalls}},
+			{1, 1, []Plane{PlanePacman, PlaneTunnels}},
+			{2, 1, []Plane{PlaneDots}},
+			{2, 2, []Plane{PlaneDots, PlaneInky, PlaneFrightened}},
+		}
+		for _, tt := range tests {
+			if got := planesAt(tn, tt.wx, tt.wy); !slices.Equal(got, tt.want) {
+				t.Errorf("window (%d,%d): planes %v, want %v", tt.wx, tt.wy, got, tt.want)
+			}
+		}
+	})
+}
+
+func TestFeatures(t *testing.T) {
+	// From (3,2) Pac-Man can only go up or down.
+	obs := testObservation(t, 3, 2)
+	f := Features(obs)
+	names := FeatureNames()
+	if len(f.Data) != len(names) || !slices.Equal(f.Shape, []int{len(names)}) {
+		t.Fatalf("shape %v with %d values for %d names", f.Shape, len(f.Data), len(names))
+	}
+	for d := entity.Up; d <= entity.Right; d++ {
+		if got, want := names[int(d)*4], []string{"up", "down", "left", "right"}[d]+"_open"; got != want {
+			t.Errorf("feature %d is %q, want %q", int(d)*4, got, want)
+		}
+	}
+	scale := float32(7 + 5)
+	want := map[string]float32{
+		"up_open":          1,
+		"up_food":          2 / scale,
+		"up_ghost":         5 / scale,
+		"up_frightened":    5 / scale,
+		"down_open":        1,
+		"down_food":        1 / scale,
+		"down_ghost":       5 / scale,
+		"down_frightened":  3 / scale,
+		"left_open":        0,
+		"left_food":        1,
+		"left_ghost":       1,
+		"left_frightened":  1,
+		"right_open":       0,
+		"right_food":       1,
+		"right_ghost":      1,
+		"right_frightened": 1,
+		"power_left":       0.5,
+	}
+	for i, name := range names {
+		if got := f.Data[i]; got != want[name] {
+			t.Errorf("%s = %v, want %v", name, got, want[name])
+		}
+	}
+}
+
+func TestParseEncoding(t *testing.T) {
+	obs := testObservation(t, 3, 1)
+	tests := []struct {
+		spec  string
+		shape []int
+	}{
+		{"planes", []int{int(NumPlanes), 5, 7}},
+		{"window:0", []int{int(NumPlanes), 1, 1}},
+		{"window:3", []int{int(NumPlanes), 7, 7}},
+		{"features", []int{len(FeatureNames())}},
+	}
+	for _, tt := range tests {
+		enc, err := ParseEncoding(tt.spec)
+		if err != nil {
+			t.Errorf("%s: %v", tt.spec, err)
+			continue
+		}
+		tn := enc.Encode(obs)
+		if len(enc.Labels) != tt.shape[0] {
+			t.Errorf("%s: %d labels for %d entries", tt.spec, len(enc.Labels), tt.shape[0])
+		}
+		if !slices.Equal(tn.Shape, tt.shape) {
+			t.Errorf("%s: shape %v, want %v", tt.spec, tn.Shape, tt.shape)
+		}
+	}
+	for _, spec := range []string{"", "window", "window:", "window:-1", "window:x", "pixels"} {
+		if _, err := ParseEncoding(spec); err == nil {
+			t.Errorf("ParseEncoding(%q) succeeded, want an error", spec)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package pathfind
+
+import (
+	"fmt"
+
+	"github.com/vinser/pacmanai/internal/maze"
+)
+
+// MaxAllPairs is the largest number of open tiles AllPairs accepts; the
+// table grows with the square of it.
+const MaxAllPairs = 2048
+
+const unreachable = ^uint16(0)
+
+// AllPairs holds the distance between every pair of open tiles.
+type AllPairs struct {
+	graph Graph
+	// node maps a tile index to its row in dist, or -1 for closed tiles.
+	node []int
+	n    int
+	dist []uint16
+}
+
+// AllPairs runs a breadth-first search from every open tile. It fails on
+// mazes with more than MaxAllPairs open tiles.
+func (g Graph) AllPairs() (*AllPairs, error) {
+	a := &AllPairs{graph: g, node: make([]int, g.m.Width()*g.m.Height())}
+	var nodes []maze.Point
+	for i := range a.node {
+		a.node[i] = -1
+	}
+	for p := range g.Nodes() {
+		a.node[g.index(p)] = len(nodes)
+		nodes = append(nodes, p)
+	}
+	a.n = len(nodes)
+	if a.n > MaxAllPairs {
+		return nil, fmt.Errorf("all-pairs distances: %d open tiles exceed the limit of %d", a.n, MaxAllPairs)
+	}
+	a.dist = make([]uint16, a.n*a.n)
+	for i := range a.dist {
+		a.dist[i] = unreachable
+	}
+	queue := make([]maze.Point, 0, a.n)
+	for src, start := range nodes {
+		row := a.dist[src*a.n : (src+1)*a.n]
+		row[src] = 0
+		queue = append(queue[:0], start)
+		for len(queue) > 0 {
+			cur := queue[0]
+			queue = queue[1:]
+			d := row[a.node[g.index(cur)]] + 1
+			for next := range g.Neighbors(cur) {
+				if j := a.node[g.index(next)]; row[j] == unreachable {
+					row[j] = d
+					queue = append(queue, next)
+				}
+			}
+		}
+	}
+	return a, nil
+}
+
+// Distance returns the number of steps from p to q, or false if either is
+// closed or q cannot be reached from p.
+func (a *AllPairs) Distance(p, q maze.Point) (int, bool) {
+	if !a.graph.inBounds(p) || !a.graph.inBounds(q) {
+		return 0, false
+	}
+	i, j := a.node[a.graph.index(p)], a.node[a.graph.index(q)]
+	if i < 0 || j < 0 || a.dist[i*a.n+j] == unreachable {
+		return 0, false
+	}
+	return int(a.dist[i*a.n+j]), true
+}
@@ -0,0 +1,120 @@
+package pathfind
+
+import (
+	"container/heap"
+
+	"github.com/vinser/pacmanai/internal/maze"
+)
+
+// Heuristic estimates the number of steps between two tiles. AStar returns
+// shortest paths only if it never overestimates.
+type Heuristic func(a, b maze.Point) int
+
+// Zero is the heuristic that turns AStar into Dijkstra's algorithm.
+func Zero(a, b maze.Point) int {
+	return 0
+}
+
+// Manhattan is the grid distance ignoring walls and tunnels.
+func Manhattan(a, b maze.Point) int {
+	return abs(a.X-b.X) + abs(a.Y-b.Y)
+}
+
+// Wrapped returns the Manhattan distance that also considers going round
+// through tunnels, which stays admissible on mazes with tunnels. Portals
+// can shortcut any distance, so mazes with portals get Zero.
+func Wrapped(m *maze.Maze) Heuristic {
+	if m.HasPortals() {
+		return Zero
+	}
+	rows, cols := false, false
+	for y := 0; y < m.Height(); y++ {
+		rows = rows || m.IsTunnelRow(y)
+	}
+	for x := 0; x < m.Width(); x++ {
+		cols = cols || m.IsTunnelColumn(x)
+	}
+	w, h := m.Width(), m.Height()
+	return func(a, b maze.Point) int {
+		dx, dy := abs(a.X-b.X), abs(a.Y-b.Y)
+		if rows {
+			dx = min(dx, w-dx)
+		}
+		if cols {
+			dy = min(dy, h-dy)
+		}
+		return dx + dy
+	}
+}
+
+// AStar returns the tiles of a shortest path from start to goal, excluding
+// start, or false if goal cannot be reached. A nil heuristic means Wrapped.
+func (g Graph) AStar(start, goal maze.Point, h Heuristic) ([]maze.Point, bool) {
+	if !g.Open(start) || !g.Open(goal) {
+		return nil, false
+	}
+	if h == nil {
+		h = Wrapped(g.m)
+	}
+	cost := map[maze.Point]int{start: 0}
+	from := map[maze.Point]maze.Point{}
+	open := &frontier{{p: start, f: h(start, goal)}}
+	for open.Len() > 0 {
+		cur := heap.Pop(open).(node)
+		if cur.p == goal {
+			var path []maze.Point
+			for p := goal; p != start; p = from[p] {
+				path = append(path, p)
+			}
+			for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+				path[i], path[j] = path[j], path[i]
+			}
+			return path, true
+		}
+		if cur.g > cost[cur.p] {
+			continue
+		}
+		for next := range g.Neighbors(cur.p) {
+			c := cur.g + 1
+			if old, seen := cost[next]; seen && c >= old {
+				continue
+			}
+			cost[next] = c
+			from[next] = cur.p
+			heap.Push(open, node{p: next, g: c, f: c + h(next, goal)})
+		}
+	}
+	return nil, false
+}
+
+// node is an A* frontier entry with its cost so far and estimated total.
+type node struct {
+	p    maze.Point
+	g, f int
+}
+
+// frontier is a min-heap of nodes ordered by estimated total cost.
+type frontier []node
+
+func (q frontier) Len() int { return len(q) }
+func (q frontier) Less(i, j int) bool {
+	if q[i].f != q[j].f {
+		return q[i].f < q[j].f
+	}
+	return q[i].g > q[j].g
+}
+func (q frontier) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *frontier) Push(x any)   { *q = append(*q, x.(node)) }
+func (q *frontier) Pop() any {
+	old := *q
+	n := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return n
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,96 @@
+package pathfind
+
+import "github.com/vinser/pacmanai/internal/maze"
+
+// DistanceMap holds the number of steps from every open tile to a target
+// tile.
+type DistanceMap struct {
+	graph  Graph
+	target maze.Point
+	dist   []int
+}
+
+type distanceKey struct {
+	target       maze.Point
+	throughDoors bool
+}
+
+// BFS computes the distance map of target with a breadth-first search
+// backwards from it.
+func (g Graph) BFS(target maze.Point) *DistanceMap {
+	f := &DistanceMap{graph: g, target: target, dist: make([]int, g.m.Width()*g.m.Height())}
+	for i := range f.dist {
+		f.dist[i] = -1
+	}
+	if !g.Open(target) {
+		return f
+	}
+	f.dist[g.index(target)] = 0
+	queue := []maze.Point{target}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		d := f.dist[g.index(cur)] + 1
+		for next := range g.Predecessors(cur) {
+			if i := g.index(next); f.dist[i] < 0 {
+				f.dist[i] = d
+				queue = append(queue, next)
+			}
+		}
+	}
+	return f
+}
+
+// Distances returns the distance map of target, computing it on first use
+// and caching it on the maze afterwards.
+func (g Graph) Distances(target maze.Point) *DistanceMap {
+	key := distanceKey{target: target, throughDoors: g.throughDoors}
+	return g.m.Memo(key, func() any { return g.BFS(target) }).(*DistanceMap)
+}
+
+// Target returns the tile the distances are measured to.
+func (f *DistanceMap) Target() maze.Point {
+	return f.target
+}
+
+// Distance returns the number of steps from p to the target, or false
+// if the target cannot be reached from p.
+func (f *DistanceMap) Distance(p maze.Point) (int, bool) {
+	if !f.graph.inBounds(p) {
+		return 0, false
+	}
+	d := f.dist[f.graph.index(p)]
+	if d < 0 {
+		return 0, false
+	}
+	return d, true
+}
+
+// Next returns the neighbor of p that is one step closer to the target,
+// preferring directions in Offsets order on ties.
+func (f *DistanceMap) Next(p maze.Point) (maze.Point, bool) {
+	d, ok := f.Distance(p)
+	if !ok || d == 0 {
+		return p, false
+	}
+	for q := range f.graph.Neighbors(p) {
+		if nd, ok := f.Distance(q); ok && nd == d-1 {
+			return q, true
+		}
+	}
+	return p, false
+}
+
+// Path returns the tiles of a shortest path from p to the target,
+// excluding p itself.
+func (f *DistanceMap) Path(p maze.Point) []maze.Point {
+	var path []maze.Point
+	for {
+		next, ok := f.Next(p)
+		if !ok {
+			return path
+		}
+		path = append(path, next)
+		p = next
+	}
+}
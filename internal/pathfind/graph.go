@@ -0,0 +1,100 @@
+// Package pathfind provides the movement topology of a maze and shortest
+// path searches over it.
+package pathfind
+
+import (
+	"iter"
+
+	"github.com/vinser/pacmanai/internal/maze"
+)
+
+// Offsets lists the unit moves in arcade priority order: up, left, down,
+// right.
+var Offsets = [4]maze.Point{{X: 0, Y: -1}, {X: -1, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 0}}
+
+// Graph is the walkable-tile connectivity of a maze. Moves wrap through
+// tunnels and follow portals; doors are open only in graphs built with
+// throughDoors. One-way portals make some moves irreversible.
+type Graph struct {
+	m            *maze.Maze
+	throughDoors bool
+}
+
+// NewGraph returns the tile graph of m. Ghosts entering or leaving the
+// house use throughDoors; Pac-Man and roaming ghosts do not.
+func NewGraph(m *maze.Maze, throughDoors bool) Graph {
+	return Graph{m: m, throughDoors: throughDoors}
+}
+
+// Maze returns the maze the graph is built on.
+func (g Graph) Maze() *maze.Maze {
+	return g.m
+}
+
+// Open reports whether p is a node of the graph.
+func (g Graph) Open(p maze.Point) bool {
+	t, err := g.m.TileAt(p.X, p.Y)
+	return err == nil && (t.Walkable() || g.throughDoors && t == maze.Door)
+}
+
+// Step returns the tile reached by moving from p by the unit offset d,
+// wrapping through tunnels and portals, and whether that tile is open.
+func (g Graph) Step(p, d maze.Point) (maze.Point, bool) {
+	q := g.m.Step(p, d)
+	return q, g.Open(q)
+}
+
+// Neighbors yields the open tiles one step away from p in Offsets order.
+func (g Graph) Neighbors(p maze.Point) iter.Seq[maze.Point] {
+	return func(yield func(maze.Point) bool) {
+		for _, d := range Offsets {
+			if q, ok := g.Step(p, d); ok && !yield(q) {
+				return
+			}
+		}
+	}
+}
+
+// Predecessors yields the open tiles from which one step leads to p. They
+// are p's neighbors unless portals are involved.
+func (g Graph) Predecessors(p maze.Point) iter.Seq[maze.Point] {
+	return func(yield func(maze.Point) bool) {
+		// Stepping onto p itself or onto an entrance leading to p arrives
+		// at p.
+		for _, c := range append([]maze.Point{p}, g.m.PortalsTo(p)...) {
+			for _, d := range Offsets {
+				q := g.m.Wrap(maze.Point{X: c.X - d.X, Y: c.Y - d.Y})
+				if !g.Open(q) {
+					continue
+				}
+				if to, _ := g.Step(q, d); to == p && !yield(q) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Nodes yields every open tile in row-major order.
+func (g Graph) Nodes() iter.Seq[maze.Point] {
+	return func(yield func(maze.Point) bool) {
+		for y := 0; y < g.m.Height(); y++ {
+			for x := 0; x < g.m.Width(); x++ {
+				p := maze.Point{X: x, Y: y}
+				if g.Open(p) && !yield(p) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// index returns the position of p in row-major tile order.
+func (g Graph) index(p maze.Point) int {
+	return p.Y*g.m.Width() + p.X
+}
+
+// inBounds reports whether p lies inside the maze.
+func (g Graph) inBounds(p maze.Point) bool {
+	return p.X >= 0 && p.X < g.m.Width() && p.Y >= 0 && p.Y < g.m.Height()
+}
@@ -0,0 +1,48 @@
+package pathfind
+
+import "github.com/vinser/pacmanai/internal/maze"
+
+// Nearest runs a breadth-first search from start and returns the tiles of
+// a shortest path to the closest tile for which goal returns true,
+// excluding start, or false if no such tile can be reached. The path is
+// empty when start itself is a goal. The search never enters tiles in
+// blocked, which may be nil; start is exempt. Among equally near goals the
+// one found first in Offsets order wins.
+func (g Graph) Nearest(start maze.Point, goal func(maze.Point) bool, blocked map[maze.Point]bool) ([]maze.Point, bool) {
+	if !g.Open(start) {
+		return nil, false
+	}
+	if goal(start) {
+		return []maze.Point{}, true
+	}
+	// from holds the tile each reached tile was entered from, indexed
+	// like the tiles of a DistanceMap.
+	from := make([]maze.Point, g.m.Width()*g.m.Height())
+	seen := make([]bool, len(from))
+	seen[g.index(start)] = true
+	queue := []maze.Point{start}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for next := range g.Neighbors(cur) {
+			k := g.index(next)
+			if seen[k] || blocked[next] {
+				continue
+			}
+			seen[k], from[k] = true, cur
+			if !goal(next) {
+				queue = append(queue, next)
+				continue
+			}
+			var path []maze.Point
+			for p := next; p != start; p = from[g.index(p)] {
+				path = append(path, p)
+			}
+			for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+				path[i], path[j] = path[j], path[i]
+			}
+			return path, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,218 @@
+package pathfind_test
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/vinser/pacmanai/internal/maze"
+	"github.com/vinser/pacmanai/internal/pathfind"
+)
+
+// portalMaze has a two-way portal 1 and a one-way portal 2.
+const portalMaze = `@oneway 2
+#########
+#C..1..B#
+#.#####.#
+#I.2P2.Y#
+#.#####.#
+#..1....#
+#########
+`
+
+// testMazes returns the default maze, a portal maze and a generated maze
+// of the given size.
+func testMazes(tb testing.TB, width, height int) map[string]*maze.Maze {
+	tb.Helper()
+	generated, err := maze.Generate(1, width, height, maze.DefaultGenOptions(height))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	portals, err := maze.Parse(strings.NewReader(portalMaze))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return map[string]*maze.Maze{
+		"default":   maze.LoadDefault(),
+		"generated": generated,
+		"portals":   portals,
+	}
+}
+
+// TestSearchesAgree checks that BFS distances, A* and Nearest path lengths
+// and the all-pairs table match for every pair of tiles, with and without
+// doors.
+// A* on every pair is slow, so the generated maze is a small one.
+func TestSearchesAgree(t *testing.T) {
+	for name, m := range testMazes(t, 17, 15) {
+		for _, throughDoors := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s/doors=%v", name, throughDoors), func(t *testing.T) {
+				t.Parallel()
+				g := pathfind.NewGraph(m, throughDoors)
+				ap, err := g.AllPairs()
+				if err != nil {
+					t.Fatal(err)
+				}
+				for to := range g.Nodes() {
+					bfs := g.BFS(to)
+					for from := range g.Nodes() {
+						d, ok := bfs.Distance(from)
+						apd, apOK := ap.Distance(from, to)
+						path, pathOK := g.AStar(from, to, nil)
+						if ok != apOK || ok != pathOK || d != apd || d != len(path) {
+							t.Fatalf("%v to %v: BFS %d %v, all-pairs %d %v, A* %d %v",
+								from, to, d, ok, apd, apOK, len(path), pathOK)
+						}
+						if got := len(bfs.Path(from)); got != d {
+							t.Fatalf("%v to %v: BFS path has %d steps, want %d", from, to, got, d)
+						}
+						if throughDoors {
+							// Nearest is slow on every pair; one graph is enough.
+							continue
+						}
+						near, nearOK := g.Nearest(from, func(p maze.Point) bool { return p == to }, nil)
+						if nearOK != ok || len(near) != d {
+							t.Fatalf("%v to %v: Nearest found %d steps %v, want %d %v", from, to, len(near), nearOK, d, ok)
+						}
+						for i, cur := range append([]maze.Point{from}, near...)[:len(near)] {
+							if !isMove(g, cur, near[i]) {
+								t.Fatalf("%v to %v: Nearest steps from %v to %v", from, to, cur, near[i])
+							}
+						}
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestOneWayPortal(t *testing.T) {
+	m, err := maze.Parse(strings.NewReader(portalMaze))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := pathfind.NewGraph(m, false)
+	in, out := maze.Point{X: 3, Y: 3}, maze.Point{X: 5, Y: 3}
+	// Stepping right from (2,3) enters the portal at (3,3) and lands on (5,3).
+	if d, _ := g.BFS(out).Distance(maze.Point{X: 2, Y: 3}); d != 1 {
+		t.Errorf("distance through the one-way portal = %d, want 1", d)
+	}
+	if q, _ := g.Step(maze.Point{X: 6, Y: 3}, maze.Point{X: -1, Y: 0}); q != out {
+		t.Errorf("stepping onto the exit of a one-way portal led to %v, want %v", q, out)
+	}
+	if _, ok := g.AStar(maze.Point{X: 2, Y: 3}, in, nil); ok {
+		t.Errorf("the entrance of a one-way portal is reachable, want it to lead away at once")
+	}
+}
+
+func TestNearestGoalsAndBlocks(t *testing.T) {
+	m, err := maze.Parse(strings.NewReader(portalMaze))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := pathfind.NewGraph(m, false)
+	start := maze.Point{X: 1, Y: 1}
+	at := func(pts ...maze.Point) func(maze.Point) bool {
+		return func(p maze.Point) bool { return slices.Contains(pts, p) }
+	}
+	tests := []struct {
+		name    string
+		goal    func(maze.Point) bool
+		blocked map[maze.Point]bool
+		want    []maze.Point
+		ok      bool
+	}{
+		{"start is a goal", at(start), nil, []maze.Point{}, true},
+		{"closer goal wins", at(maze.Point{X: 7, Y: 1}, maze.Point{X: 1, Y: 3}), nil,
+			[]maze.Point{{X: 1, Y: 2}, {X: 1, Y: 3}}, true},
+		{"through a portal", at(maze.Point{X: 4, Y: 5}), nil,
+			[]maze.Point{{X: 2, Y: 1}, {X: 3, Y: 1}, {X: 3, Y: 5}, {X: 4, Y: 5}}, true},
+		{"around a blocked tile", at(maze.Point{X: 3, Y: 1}), map[maze.Point]bool{{X: 2, Y: 1}: true},
+			[]maze.Point{{X: 1, Y: 2}, {X: 1, Y: 3}, {X: 1, Y: 4}, {X: 1, Y: 5}, {X: 2, Y: 5}, {X: 4, Y: 1}, {X: 3, Y: 1}}, true},
+		{"blocked goal", at(maze.Point{X: 2, Y: 1}), map[maze.Point]bool{{X: 2, Y: 1}: true}, nil, false},
+		{"no goal", at(), nil, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, ok := g.Nearest(start, tt.goal, tt.blocked)
+			if ok != tt.ok || ok && !slices.Equal(path, tt.want) {
+				t.Errorf("Nearest = %v %v, want %v %v", path, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func BenchmarkBFS(b *testing.B) {
+	for name, m := range testMazes(b, 27, 23) {
+		g := pathfind.NewGraph(m, false)
+		nodes := collect(g)
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				g.BFS(nodes[i%len(nodes)])
+			}
+		})
+	}
+}
+
+func BenchmarkAStar(b *testing.B) {
+	for name, m := range testMazes(b, 27, 23) {
+		g := pathfind.NewGraph(m, false)
+		nodes := collect(g)
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				from := nodes[i%len(nodes)]
+				to := nodes[(i*7+len(nodes)/2)%len(nodes)]
+				g.AStar(from, to, nil)
+			}
+		})
+	}
+}
+
+func BenchmarkNearest(b *testing.B) {
+	for name, m := range testMazes(b, 27, 23) {
+		g := pathfind.NewGraph(m, false)
+		nodes := collect(g)
+		food := func(p maze.Point) bool {
+			t, _ := m.TileAt(p.X, p.Y)
+			return t == maze.PowerPellet
+		}
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				g.Nearest(nodes[i%len(nodes)], food, nil)
+			}
+		})
+	}
+}
+
+func BenchmarkAllPairs(b *testing.B) {
+	for name, m := range testMazes(b, 27, 23) {
+		g := pathfind.NewGraph(m, false)
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := g.AllPairs(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// isMove reports whether a single step leads from a to b.
+func isMove(g pathfind.Graph, a, b maze.Point) bool {
+	for q := range g.Neighbors(a) {
+		if q == b {
+			return true
+		}
+	}
+	return false
+}
+
+// collect returns the open tiles of g in row-major order.
+func collect(g pathfind.Graph) []maze.Point {
+	var nodes []maze.Point
+	for p := range g.Nodes() {
+		nodes = append(nodes, p)
+	}
+	return nodes
+}
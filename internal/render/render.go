@@ -6,16 +6,36 @@ import (
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/vinser/pacmanai/internal/clock"
 	"github.com/vinser/pacmanai/internal/entity"
 	"github.com/vinser/pacmanai/internal/maze"
 )
 
 var (
 	styleFrightened = lipgloss.NewStyle().Foreground(lipgloss.Color("12"))
+	styleFlash      = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("15"))
 	styleEaten      = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
 	headerStyle     = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("10"))
+	styleFruit      = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("9"))
+	styleExtraLife  = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("11"))
+	stylePortal     = lipgloss.NewStyle().Foreground(lipgloss.Color("13"))
 )
 
+// HUD holds the status shown around the maze.
+type HUD struct {
+	Level int
+	// Seed is the game's random seed, shown so the game can be replayed.
+	Seed int64
+	// Fruits lists the fruits of the most recent levels.
+	Fruits []entity.FruitKind
+	// ExtraLife flashes the lives counter after a life was earned.
+	ExtraLife bool
+	// FrightFlash draws frightened ghosts white as a warning that they are
+	// about to recover.
+	FrightFlash bool
+	Clock       clock.Clock
+}
+
 func ghostAt(x, y int, ghosts []*entity.Ghost) *entity.Ghost {
 	for _, g := range ghosts {
 		if g.Pos().X == x && g.Pos().Y == y {
@@ -26,53 +46,133 @@ func ghostAt(x, y int, ghosts []*entity.Ghost) *entity.Ghost {
 }
 
 // RenderAll returns the complete screen output with game entities and stats.
-func RenderAll(m *maze.Maze, pac *entity.Pacman, ghosts []*entity.Ghost, score *entity.Score, level int) string {
+// fruit may be nil.
+func RenderAll(m *maze.Maze, pac *entity.Pacman, ghosts []*entity.Ghost, fruit *entity.Fruit, score *entity.Score, hud HUD) string {
 	var sb strings.Builder
 
 	// Draw game header
-	header := fmt.Sprintf("Score: %d   High Score: %d   Lives: %d   Level: %d\n", score.Get(), score.GetHigh(), pac.Lives(), level)
+	header := fmt.Sprintf("Score: %d   High Score: %d   Lives: %d   Level: %d   Seed: %d\n", score.Get(), score.GetHigh(), pac.Lives(), hud.Level, hud.Seed)
 	sb.WriteString(headerStyle.Render(header))
+	if hud.ExtraLife && blinkOn(hud.Clock) {
+		sb.WriteString(styleExtraLife.Render("EXTRA LIFE!"))
+	}
 	sb.WriteRune('\n')
 
 	// Draw maze with entities
 	for y := 0; y < m.Height(); y++ {
 		for x := 0; x < m.Width(); x++ {
 			if ghost := ghostAt(x, y, ghosts); ghost != nil {
-				sb.WriteString(RenderGhost(ghost))
+				sb.WriteString(RenderGhost(ghost, hud.FrightFlash))
 				continue
 			}
 			if pac.Pos().X == x && pac.Pos().Y == y {
 				sb.WriteRune('C')
 				continue
 			}
-			tile, _ := m.TileAt(x, y)
-			switch tile {
-			case maze.Wall:
-				sb.WriteRune('#')
-			case maze.Dot:
-				sb.WriteRune('.')
-			case maze.PowerPellet:
-				sb.WriteRune('o')
-			default:
-				sb.WriteRune(' ')
+			if fruit != nil && fruit.Pos().X == x && fruit.Pos().Y == y {
+				sb.WriteString(styleFruit.Render(string(fruit.Kind().Rune())))
+				continue
 			}
+			r, portal := tileRune(m, x, y)
+			if portal {
+				sb.WriteString(stylePortal.Render(string(r)))
+				continue
+			}
+			sb.WriteRune(r)
 		}
 		sb.WriteRune('\n')
 	}
 
+	// Level fruits below the maze, as in the arcade
+	sb.WriteString(RenderFruitHistory(hud.Fruits))
+
 	// Controls footer
 	sb.WriteString("\nControls: ← ↑ ↓ → — move, q — quit\n")
 	return sb.String()
 }
 
-func RenderGhost(g *entity.Ghost) string {
+// RenderFruitHistory returns the HUD line listing recent level fruits.
+func RenderFruitHistory(fruits []entity.FruitKind) string {
+	var sb strings.Builder
+	sb.WriteString("\nFruits:")
+	for _, f := range fruits {
+		sb.WriteRune(' ')
+		sb.WriteString(styleFruit.Render(string(f.Rune())))
+	}
+	sb.WriteRune('\n')
+	return sb.String()
+}
+
+// RenderBoard returns the maze with its entities as plain text without
+// styles, one line per row. fruit may be nil.
+func RenderBoard(m *maze.Maze, pac *entity.Pacman, ghosts []*entity.Ghost, fruit *entity.Fruit) string {
+	var sb strings.Builder
+	for y := 0; y < m.Height(); y++ {
+		for x := 0; x < m.Width(); x++ {
+			switch ghost := ghostAt(x, y, ghosts); {
+			case ghost != nil:
+				sb.WriteRune(ghostRune(ghost))
+			case pac.Pos().X == x && pac.Pos().Y == y:
+				sb.WriteRune('C')
+			case fruit != nil && fruit.Pos().X == x && fruit.Pos().Y == y:
+				sb.WriteRune(fruit.Kind().Rune())
+			default:
+				r, _ := tileRune(m, x, y)
+				sb.WriteRune(r)
+			}
+		}
+		sb.WriteRune('\n')
+	}
+	return sb.String()
+}
+
+// tileRune returns the character of the tile at (x, y) and whether the
+// tile is a portal.
+func tileRune(m *maze.Maze, x, y int) (rune, bool) {
+	if g, ok := m.PortalGlyph(maze.Point{X: x, Y: y}); ok {
+		return g, true
+	}
+	tile, _ := m.TileAt(x, y)
+	switch tile {
+	case maze.Wall:
+		return '#', false
+	case maze.Dot:
+		return '.', false
+	case maze.PowerPellet:
+		return 'o', false
+	case maze.Door:
+		return '-', false
+	default:
+		return ' ', false
+	}
+}
+
+// RenderGhost draws a ghost; flash shows a frightened ghost white.
+func RenderGhost(g *entity.Ghost, flash bool) string {
+	r := string(ghostRune(g))
 	switch g.State() {
 	case entity.Frightened:
-		return styleFrightened.Render("v")
+		if flash {
+			return styleFlash.Render(r)
+		}
+		return styleFrightened.Render(r)
+	case entity.Eaten:
+		return styleEaten.Render(r)
+	default:
+		return r
+	}
+}
+
+// ghostRune returns the unstyled character of a ghost: its own letter, or
+// v when frightened and x when eaten.
+func ghostRune(g *entity.Ghost) rune {
+	switch g.State() {
+	case entity.Frightened:
+		return 'v'
 	case entity.Eaten:
-		return styleEaten.Render("x")
+		return 'x'
 	default:
-		return string(g.Rune())
+		return g.Rune()
 	}
 }
 
@@ -88,9 +188,14 @@ func RenderGameOver(score *entity.Score) string {
 	return msg.String()
 }
 
-func RenderRespawning(lives int) string {
+// blinkOn reports whether blinking text is visible at the clock's current time.
+func blinkOn(clk clock.Clock) bool {
+	return (clk.Now().UnixNano()/int64(500*time.Millisecond))%2 == 0
+}
+
+func RenderRespawning(lives int, clk clock.Clock) string {
 	var flash string
-	if (time.Now().UnixNano()/int64(time.Millisecond)/500)%2 == 0 {
+	if blinkOn(clk) {
 		flash = "Respawning..."
 	} else {
 		flash = ""
@@ -102,9 +207,9 @@ func RenderRespawning(lives int) string {
 	)
 }
 
-func RenderLevelIntro(level int) string {
+func RenderLevelIntro(level int, clk clock.Clock) string {
 	var flash string
-	if (time.Now().UnixNano()/int64(time.Millisecond)/500)%2 == 0 {
+	if blinkOn(clk) {
 		flash = fmt.Sprintf("Going to Next Level %d", level)
 	}
 	return fmt.Sprintf("\n%s\nGet ready...\n", flash)
@@ -0,0 +1,76 @@
+package sim
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vinser/pacmanai/internal/clock"
+	"github.com/vinser/pacmanai/internal/entity"
+)
+
+// clocks lists the clocks a game can be stepped with. A manual clock is
+// advanced by hand before each step; a tick clock advances itself.
+func clocks() map[string]func() (clock.Clock, func()) {
+	return map[string]func() (clock.Clock, func()){
+		"manual": func() (clock.Clock, func()) {
+			c := clock.NewManual(time.Unix(1000, 0))
+			return c, func() { c.Advance(TickDuration) }
+		},
+		"ticks": func() (clock.Clock, func()) {
+			return clock.NewTicks(TickDuration), func() {}
+		},
+	}
+}
+
+func TestFrightPeriodFollowsClock(t *testing.T) {
+	for name, newClock := range clocks() {
+		t.Run(name, func(t *testing.T) {
+			clk, advance := newClock()
+			g := NewGame(Config{Seed: 1, Clock: clk})
+			g.frighten()
+			fright := g.level.FrightenedDuration
+			for elapsed := TickDuration; elapsed <= fright; elapsed += TickDuration {
+				advance()
+				obs := g.Step(NoAction).Observation
+				if !obs.PowerMode {
+					t.Fatalf("power mode ended after %v, want it to last %v", elapsed, fright)
+				}
+				if obs.PowerLeft != fright-elapsed {
+					t.Fatalf("after %v, %v of power left, want %v", elapsed, obs.PowerLeft, fright-elapsed)
+				}
+			}
+			advance()
+			g.Step(NoAction)
+			assertNoPowerMode(t, g)
+		})
+	}
+}
+
+func TestWaveChangeFollowsClock(t *testing.T) {
+	for name, newClock := range clocks() {
+		t.Run(name, func(t *testing.T) {
+			clk, advance := newClock()
+			g := NewGame(Config{Seed: 1, Clock: clk})
+			first := g.level.Waves[0]
+			for elapsed := TickDuration; elapsed <= first; elapsed += TickDuration {
+				advance()
+				g.Step(NoAction)
+				if g.Phase() != PhasePlaying {
+					t.Fatalf("phase %v after %v", g.Phase(), elapsed)
+				}
+				want := entity.Scatter
+				if elapsed == first {
+					want = entity.Chase
+				}
+				if got := g.waves.mode(); got != want {
+					t.Fatalf("after %v the wave is %v, want %v", elapsed, got, want)
+				}
+				for _, gh := range g.ghosts {
+					if s := gh.State(); (s == entity.Chase || s == entity.Scatter) && s != want {
+						t.Fatalf("after %v %s is in %v, want %v", elapsed, gh.Type(), s, want)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package sim
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/vinser/pacmanai/internal/clock"
+	"github.com/vinser/pacmanai/internal/entity"
+)
+
+// scriptedAction turns Pac-Man in a fixed pattern that changes every few
+// ticks.
+func scriptedAction(tick int) Action {
+	return []Action{MoveRight, MoveUp, NoAction, MoveLeft, MoveDown, MoveUp, MoveRight}[tick/13%7]
+}
+
+func newSeededGame(t *testing.T, seed int64, ghostAI string) *Game {
+	t.Helper()
+	brains, err := entity.ParseBrains(ghostAI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewGame(Config{Seed: seed, Brains: brains, Clock: clock.NewTicks(TickDuration)})
+}
+
+// stripMaze strips the shared maze pointer from a result so that results
+// of different games can be compared.
+func stripMaze(r Result) Result {
+	r.Observation.Maze = nil
+	return r
+}
+
+func TestSameSeedSameGame(t *testing.T) {
+	for _, ghostAI := range []string{"", "random"} {
+		a := newSeededGame(t, 42, ghostAI)
+		b := newSeededGame(t, 42, ghostAI)
+		for tick := 0; tick < 5000; tick++ {
+			action := scriptedAction(tick)
+			ra, rb := a.Step(action), b.Step(action)
+			if !reflect.DeepEqual(stripMaze(ra), stripMaze(rb)) {
+				t.Fatalf("ghost-ai %q, tick %d: results differ:\n%+v\n%+v", ghostAI, tick, stripMaze(ra), stripMaze(rb))
+			}
+			if a.Score().Get() != b.Score().Get() {
+				t.Fatalf("ghost-ai %q, tick %d: scores %d and %d", ghostAI, tick, a.Score().Get(), b.Score().Get())
+			}
+			if !reflect.DeepEqual(tiles(a), tiles(b)) {
+				t.Fatalf("ghost-ai %q, tick %d: mazes differ", ghostAI, tick)
+			}
+			if ra.Done {
+				break
+			}
+		}
+	}
+}
+
+func TestSeedChangesGame(t *testing.T) {
+	a := newSeededGame(t, 1, "random")
+	b := newSeededGame(t, 2, "random")
+	for tick := 0; tick < 200; tick++ {
+		ra, rb := a.Step(NoAction), b.Step(NoAction)
+		if !reflect.DeepEqual(ra.Observation.Ghosts, rb.Observation.Ghosts) {
+			return
+		}
+	}
+	t.Error("random ghosts moved the same way with different seeds")
+}
+
+// tiles copies the current maze of g.
+func tiles(g *Game) [][]int {
+	m := g.Maze()
+	out := make([][]int, m.Height())
+	for y := range out {
+		out[y] = make([]int, m.Width())
+		for x := range out[y] {
+			t, _ := m.TileAt(x, y)
+			out[y][x] = int(t)
+		}
+	}
+	return out
+}
@@ -0,0 +1,58 @@
+package sim
+
+import "github.com/vinser/pacmanai/internal/entity"
+
+// updateElroy turns Blinky into Cruise Elroy as the dots run out: at
+// ElroyDots1 remaining dots he speeds up and stops scattering, at
+// ElroyDots2 he speeds up again.
+func (g *Game) updateElroy() {
+	if g.elroySuspended && g.clydeOut() {
+		g.elroySuspended = false
+	}
+	stage, speed := 0, 0
+	dots1, dots2 := g.elroyDots(g.level.ElroyDots1), g.elroyDots(g.level.ElroyDots2)
+	switch dots := g.level.RemainingDots; {
+	case g.elroySuspended:
+	case dots2 > 0 && dots <= dots2:
+		stage, speed = 2, g.level.ElroySpeed2
+	case dots1 > 0 && dots <= dots1:
+		stage, speed = 1, g.level.ElroySpeed1
+	}
+	for _, gh := range g.ghosts {
+		if gh.Type() != entity.Blinky {
+			continue
+		}
+		gh.SetElroy(stage)
+		gh.SetSpeed(speed)
+		switch gh.State() {
+		case entity.Chase, entity.Scatter:
+			gh.SetState(gh.RoamState(g.waves.mode()))
+		}
+	}
+}
+
+// elroyDots scales an arcade Elroy threshold down to smaller mazes, as
+// the fruit thresholds are.
+func (g *Game) elroyDots(n int) int {
+	if total := g.level.TotalDots; total < arcadeDots {
+		return n * total / arcadeDots
+	}
+	return n
+}
+
+// suspendElroy calms Blinky down after Pac-Man loses a life. As in the
+// arcade, Cruise Elroy resumes once Clyde has left the ghost house.
+func (g *Game) suspendElroy() {
+	g.elroySuspended = true
+	g.updateElroy()
+}
+
+// clydeOut reports whether Clyde is outside the ghost house, or absent.
+func (g *Game) clydeOut() bool {
+	for _, gh := range g.ghosts {
+		if gh.Type() == entity.Clyde {
+			return gh.House() == entity.OutsideHouse
+		}
+	}
+	return true
+}
@@ -0,0 +1,124 @@
+package sim
+
+import (
+	"testing"
+
+	"github.com/vinser/pacmanai/internal/entity"
+)
+
+// blinky returns the game's Blinky.
+func blinky(t *testing.T, g *Game) *entity.Ghost {
+	t.Helper()
+	for _, gh := range g.ghosts {
+		if gh.Type() == entity.Blinky {
+			return gh
+		}
+	}
+	t.Fatal("no Blinky")
+	return nil
+}
+
+// assertElroy fails unless Blinky is in the given Cruise Elroy stage at
+// that stage's speed.
+func assertElroy(t *testing.T, g *Game, stage int) {
+	t.Helper()
+	b := blinky(t, g)
+	speed := [...]int{0, g.level.ElroySpeed1, g.level.ElroySpeed2}[stage]
+	if b.Elroy() != stage || b.Speed() != speed {
+		t.Errorf("Blinky in stage %d at speed %d, want stage %d at speed %d", b.Elroy(), b.Speed(), stage, speed)
+	}
+}
+
+func TestElroyStages(t *testing.T) {
+	tests := []struct {
+		name      string
+		level     int
+		total     int
+		remaining int
+		want      int
+	}{
+		// On level 1 the arcade stages start at 20 and 10 dots left.
+		{"arcade before", 1, arcadeDots, 21, 0},
+		{"arcade stage 1", 1, arcadeDots, 20, 1},
+		{"arcade stage 1 end", 1, arcadeDots, 11, 1},
+		{"arcade stage 2", 1, arcadeDots, 10, 2},
+		{"arcade last dot", 1, arcadeDots, 1, 2},
+		// On level 3 they start at 40 and 20.
+		{"arcade level 3 before", 3, arcadeDots, 41, 0},
+		{"arcade level 3 stage 1", 3, arcadeDots, 40, 1},
+		{"arcade level 3 stage 2", 3, arcadeDots, 20, 2},
+		// A maze with half the dots halves them.
+		{"small before", 1, arcadeDots / 2, 11, 0},
+		{"small stage 1", 1, arcadeDots / 2, 10, 1},
+		{"small stage 2", 1, arcadeDots / 2, 5, 2},
+		{"small level 3 stage 1", 3, arcadeDots / 2, 20, 1},
+		{"small level 3 stage 2", 3, arcadeDots / 2, 10, 2},
+		// Thresholds scaled below one dot never start.
+		{"tiny", 1, 10, 1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGame(Config{Seed: 1})
+			for g.level.Index < tt.level {
+				g.advanceLevel()
+			}
+			g.level.TotalDots, g.level.RemainingDots = tt.total, tt.remaining
+			g.updateElroy()
+			assertElroy(t, g, tt.want)
+			if s := blinky(t, g).State(); tt.want > 0 && s != entity.Chase {
+				t.Errorf("Cruise Elroy is in %v, want chase", s)
+			}
+		})
+	}
+}
+
+func TestElroyAfterDeath(t *testing.T) {
+	g := NewGame(Config{Seed: 1})
+	g.level.TotalDots, g.level.RemainingDots = arcadeDots, 5
+	g.updateElroy()
+	assertElroy(t, g, 2)
+
+	var clyde, pinky *entity.Ghost
+	for _, gh := range g.ghosts {
+		switch gh.Type() {
+		case entity.Clyde:
+			clyde = gh
+		case entity.Pinky:
+			pinky = gh
+		}
+	}
+	pinky.SetState(entity.Chase)
+	pinky.SetPos(g.pacman.Pos())
+	if !g.checkCollisions() {
+		t.Fatal("no life lost")
+	}
+	assertElroy(t, g, 0)
+
+	for h, where := range map[entity.HouseState]string{entity.InHouse: "waiting", entity.LeavingHouse: "leaving"} {
+		clyde.SetHouse(h)
+		g.updateElroy()
+		if !g.elroySuspended {
+			t.Fatalf("Elroy resumed while Clyde is %s", where)
+		}
+		assertElroy(t, g, 0)
+	}
+	clyde.SetHouse(entity.OutsideHouse)
+	g.updateElroy()
+	if g.elroySuspended {
+		t.Error("Elroy still suspended after Clyde left the house")
+	}
+	assertElroy(t, g, 2)
+}
+
+func TestElroyNextLevel(t *testing.T) {
+	g := NewGame(Config{Seed: 1})
+	g.suspendElroy()
+	g.advanceLevel()
+	if g.elroySuspended {
+		t.Fatal("Elroy still suspended on the next level")
+	}
+	assertElroy(t, g, 0)
+	g.level.TotalDots, g.level.RemainingDots = arcadeDots, 1
+	g.updateElroy()
+	assertElroy(t, g, 2)
+}
@@ -0,0 +1,603 @@
+// Package sim implements the game rules as a deterministic, terminal-free
+// simulation that advances one logical tick per Step.
+package sim
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/vinser/pacmanai/internal/clock"
+	"github.com/vinser/pacmanai/internal/entity"
+	"github.com/vinser/pacmanai/internal/level"
+	"github.com/vinser/pacmanai/internal/maze"
+)
+
+// TickDuration is the amount of game time covered by a single Step.
+const TickDuration = 50 * time.Millisecond
+
+const (
+	fruitPeriod      = 10 * time.Second
+	extraLifeFlash   = 2 * time.Second
+	respawnPeriod    = 3 * time.Second
+	levelIntroPeriod = 3 * time.Second
+)
+
+// fruitDots lists how many dots must be eaten for each bonus fruit to
+// appear on the 244-dot arcade maze. Smaller mazes scale them down.
+var fruitDots = []int{70, 170}
+
+const arcadeDots = 244
+
+// flashPeriod is the length of one white and blue warning flash of
+// frightened ghosts.
+const flashPeriod = 400 * time.Millisecond
+
+// fruitHistorySize is how many level fruits the HUD shows.
+const fruitHistorySize = 7
+
+// Phase is the current stage of the game.
+type Phase int
+
+const (
+	PhasePlaying Phase = iota
+	PhaseRespawning
+	PhaseGameOver
+	PhaseLevelIntro
+)
+
+// Action is a player command applied at the start of a tick. Pac-Man keeps
+// moving on his own; an action only buffers the next turn.
+type Action int
+
+const (
+	NoAction Action = iota
+	MoveUp
+	MoveDown
+	MoveLeft
+	MoveRight
+)
+
+// ActionFor returns the action that turns Pac-Man in direction d.
+func ActionFor(d entity.Direction) Action {
+	switch d {
+	case entity.Up:
+		return MoveUp
+	case entity.Down:
+		return MoveDown
+	case entity.Left:
+		return MoveLeft
+	case entity.Right:
+		return MoveRight
+	}
+	return NoAction
+}
+
+// Config holds the parameters a game is started with.
+type Config struct {
+	HighScore int
+	// Seed initializes the game's random source. The same seed and the same
+	// sequence of actions always produce the same game.
+	Seed int64
+	// ExtraLife is the score that earns an extra life; zero selects 10,000
+	// and a negative value disables extra lives, as in Score.SetBonusLife.
+	ExtraLife int
+	// ExtraLifeEvery repeats the extra life every that many points after
+	// ExtraLife. Zero awards it only once.
+	ExtraLifeEvery int
+	// Levels lists the rules of every level. When nil, the arcade
+	// progression is used.
+	Levels level.Table
+	// Mazes supplies the maze of every level. When nil, the built-in
+	// default maze is used.
+	Mazes level.MazeSource
+	// Brains selects the behavior of individual ghosts. Ghosts without an
+	// entry use the arcade brain.
+	Brains map[entity.GhostType]entity.GhostBrain
+	// Reward defines the training reward paid next to the score. When nil,
+	// the reward equals the points scored.
+	Reward *entity.RewardConfig
+	// Clock drives all timed phases. When nil, a tick-counted clock advanced
+	// by TickDuration on every Step is used.
+	Clock clock.Clock
+}
+
+// Game holds the complete state of a single game.
+type Game struct {
+	level           *level.Config
+	pacman          *entity.Pacman
+	ghosts          []*entity.Ghost
+	score           *entity.Score
+	reward          *entity.Reward
+	fruit           *entity.Fruit
+	fruitUntil      time.Time
+	fruitsShown     int
+	fruitHistory    []entity.FruitKind
+	extraLifeUntil  time.Time
+	phase           Phase
+	clock           clock.Clock
+	levels          level.Table
+	mazes           level.MazeSource
+	seed            int64
+	rng             *rand.Rand
+	waves           *waveScheduler
+	tick            int
+	lastStep        time.Time
+	lastDotEaten    time.Time
+	dotCounters     map[*entity.Ghost]int
+	globalDots      int // dots since the last lost life, or -1 if unused
+	elroySuspended  bool
+	powerMode       bool
+	powerModeUntil  time.Time
+	respawnUntil    time.Time
+	levelIntroUntil time.Time
+	events          []Event
+}
+
+// NewGame initializes a game with maze, player, and ghosts.
+func NewGame(cfg Config) *Game {
+	s := entity.NewScore()
+	s.SetHigh(cfg.HighScore)
+	s.SetBonusLife(cfg.ExtraLife, cfg.ExtraLifeEvery)
+	clk := cfg.Clock
+	if clk == nil {
+		clk = clock.NewTicks(TickDuration)
+	}
+	rewardCfg := entity.DefaultRewardConfig()
+	if cfg.Reward != nil {
+		rewardCfg = *cfg.Reward
+	}
+	lvl := level.Create(1, cfg.Levels, cfg.Mazes)
+	var ghosts []*entity.Ghost
+	for _, t := range []entity.GhostType{entity.Blinky, entity.Inky, entity.Pinky, entity.Clyde} {
+		ghosts = append(ghosts, entity.NewGhost(t, lvl.Spawn(t.String()), cfg.Brains[t]))
+	}
+	g := &Game{
+		level:       lvl,
+		pacman:      entity.NewPacman(lvl.Spawn(maze.SpawnPacman)),
+		ghosts:      ghosts,
+		score:       s,
+		reward:      entity.NewReward(rewardCfg),
+		phase:       PhasePlaying,
+		clock:       clk,
+		levels:      cfg.Levels,
+		mazes:       cfg.Mazes,
+		seed:        cfg.Seed,
+		rng:         rand.New(rand.NewSource(cfg.Seed)),
+		waves:       newWaveScheduler(lvl.Waves),
+		lastStep:    clk.Now(),
+		dotCounters: map[*entity.Ghost]int{},
+		globalDots:  -1,
+	}
+	g.resetGhosts()
+	g.recordFruit()
+	g.reward.Start(lvl.Maze, g.pacman.Pos())
+	return g
+}
+
+// Step advances the game by exactly one tick, applying action first.
+func (g *Game) Step(action Action) Result {
+	g.events = nil
+	g.tick++
+	if t, ok := g.clock.(clock.Ticker); ok {
+		t.Tick()
+	}
+	playing := g.phase == PhasePlaying
+	g.step(action)
+	g.awardExtraLives()
+	if playing {
+		g.payTick()
+	}
+	rewards, reward := g.reward.Take()
+	return Result{
+		Observation: g.Observe(),
+		Events:      g.events,
+		Reward:      reward,
+		Rewards:     rewards,
+		Done:        g.phase == PhaseGameOver,
+	}
+}
+
+// payTick pays the time penalty and the shaping reward of a tick of play.
+func (g *Game) payTick() {
+	pac := g.pacman.Pos()
+	g.reward.Tick(pac)
+	if g.phase == PhaseGameOver {
+		g.reward.End(pac)
+		return
+	}
+	g.reward.Shape(g.level.Maze, pac)
+}
+
+func (g *Game) step(action Action) {
+	now := g.clock.Now()
+	elapsed := now.Sub(g.lastStep)
+	g.lastStep = now
+	switch g.phase {
+	case PhaseGameOver:
+		return
+	case PhaseLevelIntro:
+		if now.After(g.levelIntroUntil) {
+			g.phase = PhasePlaying
+		}
+		return
+	case PhaseRespawning:
+		if now.After(g.respawnUntil) {
+			g.phase = PhasePlaying
+		}
+		return
+	}
+
+	if action != NoAction {
+		g.pacman.Queue(directionFor(action))
+	}
+
+	for n := g.pacman.Advance(g.pacmanSpeed(), TickDuration); n > 0; n-- {
+		g.movePacman()
+		if g.level.RemainingDots < 1 {
+			g.advanceLevel()
+			return
+		}
+		if g.checkCollisions() {
+			return
+		}
+	}
+
+	g.updatePowerMode()
+	g.updateFruit(now)
+	g.updateHouse(now)
+	g.updateElroy()
+
+	// The wave timer is paused while ghosts are frightened.
+	if !g.powerMode && g.waves.advance(elapsed) {
+		g.switchGhostMode(g.waves.mode())
+	}
+
+	for _, gh := range g.ghosts {
+		for n := gh.Advance(g.ghostSpeed(gh), TickDuration); n > 0; n-- {
+			gh.Step(g.world())
+		}
+	}
+	g.checkCollisions()
+}
+
+// pacmanSpeed returns the speed percent Pac-Man currently moves at.
+func (g *Game) pacmanSpeed() int {
+	if g.powerMode {
+		return g.level.PacmanFrightSpeed
+	}
+	return g.level.PacmanSpeed
+}
+
+// ghostSpeed returns the speed percent gh currently moves at. Eaten eyes
+// are never slowed; tunnels slow ghosts down even when frightened.
+func (g *Game) ghostSpeed(gh *entity.Ghost) int {
+	pos := gh.Pos()
+	switch {
+	case gh.State() == entity.Eaten:
+		return g.level.EatenSpeed
+	case g.level.Maze.IsTunnel(pos.X, pos.Y):
+		return g.level.GhostTunnelSpeed
+	case gh.State() == entity.Frightened:
+		return g.level.GhostFrightSpeed
+	case gh.Speed() > 0:
+		return gh.Speed()
+	default:
+		return g.level.GhostSpeed
+	}
+}
+
+// directionFor returns the direction a movement action asks for.
+func directionFor(action Action) entity.Direction {
+	switch action {
+	case MoveUp:
+		return entity.Up
+	case MoveDown:
+		return entity.Down
+	case MoveLeft:
+		return entity.Left
+	default:
+		return entity.Right
+	}
+}
+
+func (g *Game) movePacman() {
+	g.pacman.Move(g.level.Maze)
+
+	pos := g.pacman.Pos()
+	if g.fruit != nil && g.fruit.Pos() == pos {
+		g.score.Add(g.fruit.Points())
+		g.emit(FruitEaten, g.fruit.Points(), pos)
+		g.fruit = nil
+	}
+	switch g.level.Maze.EatItem(pos.X, pos.Y) {
+	case maze.Dot:
+		g.score.Add(10)
+		g.level.RemainingDots--
+		g.countHouseDot(g.clock.Now())
+		g.emit(DotEaten, 10, pos)
+	case maze.PowerPellet:
+		g.score.Add(50)
+		g.level.RemainingDots--
+		g.countHouseDot(g.clock.Now())
+		g.emit(PowerPelletEaten, 50, pos)
+		g.frighten()
+	}
+}
+
+// frighten applies a power pellet. Roaming ghosts reverse and all but eaten
+// eyes turn blue for the level's frightened duration; a pellet eaten while
+// they are still blue restarts the timer and the ghost points at 200. With
+// no frightened time left on high levels, ghosts only reverse.
+func (g *Game) frighten() {
+	for _, gh := range g.ghosts {
+		switch gh.State() {
+		case entity.Chase, entity.Scatter:
+			gh.Reverse()
+		}
+	}
+	if g.level.FrightenedDuration <= 0 {
+		return
+	}
+	g.powerMode = true
+	g.powerModeUntil = g.clock.Now().Add(g.level.FrightenedDuration)
+	g.score.ResetGhostStreak()
+	for _, gh := range g.ghosts {
+		if gh.State() != entity.Eaten {
+			gh.SetState(entity.Frightened)
+		}
+	}
+}
+
+// updateFruit puts bonus fruit on the maze's fruit spot once enough dots
+// are eaten and removes it when it times out. Mazes without a fruit spot
+// get no fruit.
+func (g *Game) updateFruit(now time.Time) {
+	if g.fruit != nil {
+		if now.After(g.fruitUntil) {
+			g.fruit = nil
+		}
+		return
+	}
+	spot, ok := g.level.Maze.FruitSpot()
+	if !ok || g.fruitsShown >= len(fruitDots) {
+		return
+	}
+	need := fruitDots[g.fruitsShown]
+	if total := g.level.TotalDots; total < arcadeDots {
+		need = need * total / arcadeDots
+	}
+	if g.level.TotalDots-g.level.RemainingDots < need {
+		return
+	}
+	g.fruit = entity.NewFruit(g.level.Fruit, g.level.FruitPoints, entity.Position{X: spot.X, Y: spot.Y})
+	g.fruitUntil = now.Add(fruitPeriod)
+	g.fruitsShown++
+}
+
+// awardExtraLives grants a life for every score threshold crossed.
+func (g *Game) awardExtraLives() {
+	for _, threshold := range g.score.TakeCrossings() {
+		if g.phase == PhaseGameOver {
+			return
+		}
+		g.pacman.AddLife()
+		g.emit(ExtraLife, threshold, g.pacman.Pos())
+		g.extraLifeUntil = g.clock.Now().Add(extraLifeFlash)
+	}
+}
+
+// recordFruit adds the current level's fruit to the HUD history.
+func (g *Game) recordFruit() {
+	g.fruitHistory = append(g.fruitHistory, g.level.Fruit)
+	if len(g.fruitHistory) > fruitHistorySize {
+		g.fruitHistory = g.fruitHistory[len(g.fruitHistory)-fruitHistorySize:]
+	}
+}
+
+func (g *Game) updatePowerMode() {
+	if g.powerMode && g.clock.Now().After(g.powerModeUntil) {
+		g.endPowerMode()
+		for _, gh := range g.ghosts {
+			if gh.State() == entity.Frightened {
+				gh.SetState(gh.RoamState(g.waves.mode()))
+			}
+		}
+	}
+}
+
+// endPowerMode stops frightened time and restarts the ghost points at 200.
+// Callers put frightened ghosts back to their roaming state.
+func (g *Game) endPowerMode() {
+	g.powerMode = false
+	g.powerModeUntil = time.Time{}
+	g.score.ResetGhostStreak()
+}
+
+// FrightFlash reports whether frightened ghosts are currently shown white,
+// which they alternate with blue during the warning flashes before they
+// recover.
+func (g *Game) FrightFlash() bool {
+	if !g.powerMode {
+		return false
+	}
+	warning := time.Duration(g.level.FrightFlashes) * flashPeriod
+	left := g.powerModeUntil.Sub(g.clock.Now())
+	if left <= 0 || left > warning {
+		return false
+	}
+	return (warning-left)/(flashPeriod/2)%2 == 0
+}
+
+// switchGhostMode moves roaming ghosts into the given mode, reversing them
+// as in the arcade.
+func (g *Game) switchGhostMode(mode entity.GhostState) {
+	for _, gh := range g.ghosts {
+		switch gh.State() {
+		case entity.Chase, entity.Scatter:
+			gh.SetState(gh.RoamState(mode))
+			gh.Reverse()
+		}
+	}
+}
+
+// checkCollisions resolves Pac-Man meeting a ghost and reports whether the
+// current tick must stop (a life was lost or the game ended).
+func (g *Game) checkCollisions() bool {
+	pac := g.pacman.Pos()
+	for _, gh := range g.ghosts {
+		if pac != gh.Pos() {
+			continue
+		}
+		switch gh.State() {
+		case entity.Frightened:
+			before := g.score.Get()
+			g.score.AddGhostPoints()
+			g.emit(GhostEaten, g.score.Get()-before, pac)
+			gh.SetState(entity.Eaten)
+			return false
+		case entity.Chase, entity.Scatter:
+			g.pacman.LoseLife()
+			g.emit(LifeLost, 0, pac)
+			if g.pacman.IsDead() {
+				g.phase = PhaseGameOver
+				g.emit(GameEnded, 0, pac)
+				return true
+			}
+			// Enter respawn mode
+			g.fruit = nil
+			g.endPowerMode()
+			g.waves = newWaveScheduler(g.level.Waves)
+			g.globalDots = 0
+			g.pacman.SetPos(g.pacman.Home())
+			g.resetGhosts()
+			g.suspendElroy()
+			g.phase = PhaseRespawning
+			g.respawnUntil = g.clock.Now().Add(respawnPeriod)
+			return true
+		}
+	}
+	return false
+}
+
+func (g *Game) advanceLevel() {
+	g.emit(LevelCleared, 0, g.pacman.Pos())
+	g.level = level.Create(g.level.Index+1, g.levels, g.mazes)
+	g.waves = newWaveScheduler(g.level.Waves)
+	g.fruit = nil
+	g.fruitsShown = 0
+	g.endPowerMode()
+	g.recordFruit()
+	// Each level's maze may place everyone differently.
+	g.pacman.SetHome(g.level.Spawn(maze.SpawnPacman))
+	for _, gh := range g.ghosts {
+		gh.SetHome(g.level.Spawn(gh.Type().String()))
+	}
+	g.pacman.SetPos(g.pacman.Home())
+	g.dotCounters = map[*entity.Ghost]int{}
+	g.globalDots = -1
+	g.elroySuspended = false
+	g.updateElroy()
+	g.resetGhosts()
+	g.phase = PhaseLevelIntro
+	g.levelIntroUntil = g.clock.Now().Add(levelIntroPeriod)
+}
+
+// world returns the view of the game that ghost decisions are based on.
+func (g *Game) world() entity.World {
+	return entity.World{
+		Maze:      g.level.Maze,
+		Pacman:    g.pacman.Pos(),
+		PacmanDir: g.pacman.Dir(),
+		Ghosts:    g.ghostViews(),
+		PowerMode: g.powerMode,
+		Mode:      g.waves.mode(),
+		Rand:      g.rng,
+	}
+}
+
+// ghostViews returns read-only snapshots of all ghosts.
+func (g *Game) ghostViews() []entity.GhostView {
+	views := make([]entity.GhostView, len(g.ghosts))
+	for i, gh := range g.ghosts {
+		views[i] = gh.View()
+	}
+	return views
+}
+
+// rewardKinds maps the events that are paid for to their reward terms.
+var rewardKinds = map[EventKind]entity.RewardKind{
+	DotEaten:         entity.RewardDot,
+	PowerPelletEaten: entity.RewardPowerPellet,
+	GhostEaten:       entity.RewardGhost,
+	FruitEaten:       entity.RewardFruit,
+	LifeLost:         entity.RewardDeath,
+	LevelCleared:     entity.RewardLevelClear,
+}
+
+func (g *Game) emit(kind EventKind, points int, pos entity.Position) {
+	g.events = append(g.events, Event{Kind: kind, Points: points, Pos: pos})
+	if rk, ok := rewardKinds[kind]; ok {
+		g.reward.Earn(rk, points, pos)
+	}
+}
+
+// Phase returns the current stage of the game.
+func (g *Game) Phase() Phase {
+	return g.phase
+}
+
+// Seed returns the seed the game's random source was initialized with.
+func (g *Game) Seed() int64 {
+	return g.seed
+}
+
+// Clock returns the time source driving the game.
+func (g *Game) Clock() clock.Clock {
+	return g.clock
+}
+
+// Level returns the current level index.
+func (g *Game) Level() int {
+	return g.level.Index
+}
+
+// Maze returns the maze of the current level.
+func (g *Game) Maze() *maze.Maze {
+	return g.level.Maze
+}
+
+// Pacman returns the player entity.
+func (g *Game) Pacman() *entity.Pacman {
+	return g.pacman
+}
+
+// Ghosts returns the ghost entities.
+func (g *Game) Ghosts() []*entity.Ghost {
+	return g.ghosts
+}
+
+// Fruit returns the bonus fruit on the board, or nil.
+func (g *Game) Fruit() *entity.Fruit {
+	return g.fruit
+}
+
+// FruitHistory returns the fruits of the most recent levels, oldest first.
+func (g *Game) FruitHistory() []entity.FruitKind {
+	return g.fruitHistory
+}
+
+// ExtraLifeFlash reports whether an extra life was earned recently enough
+// for the HUD to highlight it.
+func (g *Game) ExtraLifeFlash() bool {
+	return !g.clock.Now().After(g.extraLifeUntil)
+}
+
+// Reward returns the training reward paid so far.
+func (g *Game) Reward() *entity.Reward {
+	return g.reward
+}
+
+// Score returns the game score.
+func (g *Game) Score() *entity.Score {
+	return g.score
+}
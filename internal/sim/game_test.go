@@ -0,0 +1,144 @@
+package sim
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/vinser/pacmanai/internal/entity"
+	"github.com/vinser/pacmanai/internal/level"
+	"github.com/vinser/pacmanai/internal/maze"
+	"github.com/vinser/pacmanai/internal/pathfind"
+)
+
+// smallMaze is a loop with every spawn and a fruit spot.
+const smallMaze = `#########
+#C.....B#
+#.#####.#
+#I..P.FY#
+#########
+`
+
+// newMazeGame starts a game played on every level on the maze in text.
+func newMazeGame(t *testing.T, text string) *Game {
+	t.Helper()
+	m, err := maze.Parse(strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewGame(Config{Seed: 1, Mazes: level.FixedMaze(m)})
+}
+
+// assertNoPowerMode fails if any trace of frightened time is left.
+func assertNoPowerMode(t *testing.T, g *Game) {
+	t.Helper()
+	obs := g.Observe()
+	if obs.PowerMode || obs.PowerLeft != 0 || g.FrightFlash() {
+		t.Errorf("power mode left over: PowerMode=%v PowerLeft=%v FrightFlash=%v", obs.PowerMode, obs.PowerLeft, g.FrightFlash())
+	}
+	for _, gh := range g.Ghosts() {
+		if gh.State() == entity.Frightened {
+			t.Errorf("%s is still frightened", gh.Type())
+		}
+	}
+	if got, want := g.pacmanSpeed(), g.level.PacmanSpeed; got != want {
+		t.Errorf("Pac-Man speed = %d, want the normal %d", got, want)
+	}
+	before := g.score.Get()
+	g.score.AddGhostPoints()
+	if got := g.score.Get() - before; got != 200 {
+		t.Errorf("next ghost is worth %d, want 200", got)
+	}
+}
+
+func TestDeathEndsPowerMode(t *testing.T) {
+	g := NewGame(Config{Seed: 1})
+	g.frighten()
+	// Eat one ghost so the streak is raised, then meet a roaming one.
+	g.score.AddGhostPoints()
+	killer := g.ghosts[0]
+	killer.SetState(entity.Chase)
+	killer.SetPos(g.pacman.Pos())
+	if !g.checkCollisions() {
+		t.Fatal("collision with a chasing ghost did not end the tick")
+	}
+	if g.Phase() != PhaseRespawning {
+		t.Fatalf("phase = %v, want respawning", g.Phase())
+	}
+	for g.Phase() != PhasePlaying {
+		g.Step(NoAction)
+	}
+	assertNoPowerMode(t, g)
+}
+
+func TestLevelClearEndsPowerMode(t *testing.T) {
+	g := NewGame(Config{Seed: 1})
+	g.frighten()
+	g.score.AddGhostPoints()
+	g.advanceLevel()
+	assertNoPowerMode(t, g)
+}
+
+func TestFruitAppearsOnFruitSpot(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want *entity.Position
+	}{
+		{"fruit spot", smallMaze, &entity.Position{X: 6, Y: 3}},
+		{"no fruit spot", strings.Replace(smallMaze, "F", ".", 1), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newMazeGame(t, tt.text)
+			// Eating every dot passes both fruit thresholds.
+			g.level.RemainingDots = 0
+			g.updateFruit(g.clock.Now())
+			switch {
+			case tt.want == nil && g.fruit != nil:
+				t.Errorf("fruit appeared at %v", g.fruit.Pos())
+			case tt.want != nil && g.fruit == nil:
+				t.Error("no fruit appeared")
+			case tt.want != nil && g.fruit.Pos() != *tt.want:
+				t.Errorf("fruit at %v, want %v", g.fruit.Pos(), *tt.want)
+			}
+		})
+	}
+}
+
+func TestGameOverReward(t *testing.T) {
+	cfg := entity.RewardConfig{Death: -100, Shaping: 0.5, Gamma: 0.9}
+	g := NewGame(Config{Seed: 1, Reward: &cfg})
+	for g.pacman.Lives() > 1 {
+		g.pacman.LoseLife()
+	}
+	pac := g.pacman.Pos()
+	food, ok := pathfind.NewGraph(g.level.Maze, false).Nearest(maze.Point{X: pac.X, Y: pac.Y}, func(p maze.Point) bool {
+		tile, _ := g.level.Maze.TileAt(p.X, p.Y)
+		return tile == maze.Dot || tile == maze.PowerPellet
+	}, nil)
+	if !ok || len(food) == 0 {
+		t.Fatal("no food in reach of the spawn")
+	}
+	// Neither Pac-Man nor the ghost crosses a tile on the first tick, so
+	// they meet where they stand.
+	killer := g.ghosts[0]
+	killer.SetState(entity.Chase)
+	killer.SetPos(pac)
+	r := g.Step(NoAction)
+	if !r.Done {
+		t.Fatalf("game not over: phase %v", g.Phase())
+	}
+	// The final state has zero potential, so the last shaping term gives
+	// back the potential of the spawn.
+	want := []entity.RewardEvent{
+		{Kind: entity.RewardDeath, Value: -100, Pos: pac},
+		{Kind: entity.RewardShaping, Value: 0.5 * float64(len(food)), Pos: pac},
+	}
+	if !slices.Equal(r.Rewards, want) {
+		t.Errorf("rewards %+v, want %+v", r.Rewards, want)
+	}
+	if r.Reward != want[0].Value+want[1].Value {
+		t.Errorf("reward %v, want %v", r.Reward, want[0].Value+want[1].Value)
+	}
+}
@@ -0,0 +1,113 @@
+package sim
+
+import (
+	"time"
+
+	"github.com/vinser/pacmanai/internal/entity"
+)
+
+// releaseOrder is the order in which waiting ghosts leave the house.
+var releaseOrder = []entity.GhostType{entity.Blinky, entity.Pinky, entity.Inky, entity.Clyde}
+
+// dotLimit returns how many dots Pac-Man must eat while a ghost is next in
+// line before it leaves the house.
+func dotLimit(level int, t entity.GhostType) int {
+	switch {
+	case level == 1 && t == entity.Inky:
+		return 30
+	case level == 1 && t == entity.Clyde:
+		return 60
+	case level == 2 && t == entity.Clyde:
+		return 50
+	default:
+		return 0
+	}
+}
+
+// globalDotLimit returns how many dots Pac-Man must eat after losing a life
+// before a ghost leaves the house. Until Clyde leaves on it, this one count
+// replaces the per-ghost counters.
+func globalDotLimit(t entity.GhostType) int {
+	switch t {
+	case entity.Pinky:
+		return 7
+	case entity.Inky:
+		return 17
+	case entity.Clyde:
+		return 32
+	default:
+		return 0
+	}
+}
+
+// noDotTimeout returns how long Pac-Man may go without eating a dot before
+// the next waiting ghost is released anyway.
+func noDotTimeout(level int) time.Duration {
+	if level < 5 {
+		return 4 * time.Second
+	}
+	return 3 * time.Second
+}
+
+// nextInHouse returns the waiting ghost that is released next, or nil.
+func (g *Game) nextInHouse() *entity.Ghost {
+	for _, t := range releaseOrder {
+		for _, gh := range g.ghosts {
+			if gh.Type() == t && gh.House() == entity.InHouse {
+				return gh
+			}
+		}
+	}
+	return nil
+}
+
+// countHouseDot credits an eaten dot to the global counter while it is in
+// use, or else to the ghost next in line.
+func (g *Game) countHouseDot(now time.Time) {
+	g.lastDotEaten = now
+	if g.globalDots >= 0 {
+		g.globalDots++
+	} else if gh := g.nextInHouse(); gh != nil {
+		g.dotCounters[gh]++
+	}
+}
+
+// updateHouse releases the next waiting ghost once its dot counter, or the
+// global counter after a lost life, is full or Pac-Man has not eaten a dot
+// for too long.
+func (g *Game) updateHouse(now time.Time) {
+	gh := g.nextInHouse()
+	if gh == nil {
+		return
+	}
+	count, limit := g.dotCounters[gh], dotLimit(g.level.Index, gh.Type())
+	if g.globalDots >= 0 {
+		count, limit = g.globalDots, globalDotLimit(gh.Type())
+	}
+	switch {
+	case count >= limit:
+		gh.Release()
+		if gh.Type() == entity.Clyde {
+			g.globalDots = -1
+		}
+	case now.Sub(g.lastDotEaten) >= noDotTimeout(g.level.Index):
+		gh.Release()
+		g.lastDotEaten = now
+	}
+}
+
+// resetGhosts puts every ghost back at its home, waiting inside the house
+// if its home is there.
+func (g *Game) resetGhosts() {
+	for _, gh := range g.ghosts {
+		home := gh.Home()
+		gh.SetPos(home)
+		gh.SetState(gh.RoamState(g.waves.mode()))
+		if g.level.Maze.InHouse(home.X, home.Y) {
+			gh.SetHouse(entity.InHouse)
+		} else {
+			gh.SetHouse(entity.OutsideHouse)
+		}
+	}
+	g.lastDotEaten = g.clock.Now()
+}
@@ -0,0 +1,126 @@
+package sim
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/vinser/pacmanai/internal/clock"
+	"github.com/vinser/pacmanai/internal/entity"
+)
+
+func TestHouseRelease(t *testing.T) {
+	const (
+		pinky = entity.Pinky
+		inky  = entity.Inky
+		clyde = entity.Clyde
+	)
+	tests := []struct {
+		name  string
+		level int
+		died  bool
+		dots  int
+		wait  time.Duration
+		want  []entity.GhostType
+	}{
+		{"level 1 start", 1, false, 0, 0, []entity.GhostType{pinky}},
+		{"level 1 inky short", 1, false, 29, 0, []entity.GhostType{pinky}},
+		{"level 1 inky", 1, false, 30, 0, []entity.GhostType{pinky, inky}},
+		{"level 1 clyde short", 1, false, 89, 0, []entity.GhostType{pinky, inky}},
+		{"level 1 clyde", 1, false, 90, 0, []entity.GhostType{pinky, inky, clyde}},
+		{"level 2 clyde short", 2, false, 49, 0, []entity.GhostType{pinky, inky}},
+		{"level 2 clyde", 2, false, 50, 0, []entity.GhostType{pinky, inky, clyde}},
+		{"level 3", 3, false, 0, 0, []entity.GhostType{pinky, inky, clyde}},
+		{"global pinky short", 1, true, 6, 0, nil},
+		{"global pinky", 1, true, 7, 0, []entity.GhostType{pinky}},
+		{"global inky", 1, true, 17, 0, []entity.GhostType{pinky, inky}},
+		{"global clyde short", 1, true, 31, 0, []entity.GhostType{pinky, inky}},
+		{"global clyde", 1, true, 32, 0, []entity.GhostType{pinky, inky, clyde}},
+		// The global counter holds ghosts back even where their own limit
+		// is zero.
+		{"global on level 3", 3, true, 6, 0, nil},
+		{"no dots short", 1, false, 0, 3800 * time.Millisecond, []entity.GhostType{pinky}},
+		{"no dots", 1, false, 0, 4 * time.Second, []entity.GhostType{pinky, inky}},
+		{"no dots twice", 1, false, 0, 8 * time.Second, []entity.GhostType{pinky, inky, clyde}},
+		{"no dots after a dot", 1, false, 1, 3900 * time.Millisecond, []entity.GhostType{pinky}},
+		{"no dots on level 5", 5, true, 0, 3 * time.Second, []entity.GhostType{pinky}},
+		{"no dots twice on level 5", 5, true, 0, 6 * time.Second, []entity.GhostType{pinky, inky}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clk := clock.NewTicks(TickDuration)
+			g := NewGame(Config{Seed: 1, Clock: clk})
+			for g.level.Index < tt.level {
+				g.advanceLevel()
+			}
+			if tt.died {
+				killer := g.ghosts[0]
+				killer.SetState(entity.Chase)
+				killer.SetPos(g.pacman.Pos())
+				if !g.checkCollisions() {
+					t.Fatal("no life lost")
+				}
+			}
+			var waiting, released []entity.GhostType
+			for _, gh := range g.ghosts {
+				if gh.House() == entity.InHouse {
+					waiting = append(waiting, gh.Type())
+				}
+			}
+			// tick moves the clock on, eats a dot if asked and records the
+			// ghosts that leave.
+			tick := func(dot bool) {
+				clk.Tick()
+				if dot {
+					g.countHouseDot(clk.Now())
+				}
+				g.updateHouse(clk.Now())
+				for _, gh := range g.ghosts {
+					if gh.House() != entity.InHouse && slices.Contains(waiting, gh.Type()) &&
+						!slices.Contains(released, gh.Type()) {
+						released = append(released, gh.Type())
+					}
+				}
+			}
+			// Ghosts without a dot limit leave on the first ticks.
+			for range 3 {
+				tick(false)
+			}
+			for range tt.dots {
+				tick(true)
+			}
+			for range tt.wait / TickDuration {
+				tick(false)
+			}
+			if !slices.Equal(released, tt.want) {
+				t.Errorf("released %v, want %v", released, tt.want)
+			}
+		})
+	}
+}
+
+func TestGlobalCounterEndsWithClyde(t *testing.T) {
+	clk := clock.NewTicks(TickDuration)
+	g := NewGame(Config{Seed: 1, Clock: clk})
+	killer := g.ghosts[0]
+	killer.SetState(entity.Chase)
+	killer.SetPos(g.pacman.Pos())
+	if !g.checkCollisions() {
+		t.Fatal("no life lost")
+	}
+	for range globalDotLimit(entity.Clyde) {
+		clk.Tick()
+		g.countHouseDot(clk.Now())
+		g.updateHouse(clk.Now())
+	}
+	if g.globalDots != -1 {
+		t.Fatalf("global counter at %d after Clyde left, want it unused", g.globalDots)
+	}
+	// Back on the ghosts' own counters, dots go to the ghost next in line.
+	clyde := g.ghosts[3]
+	clyde.SetHouse(entity.InHouse)
+	g.countHouseDot(clk.Now())
+	if got := g.dotCounters[clyde]; got != 1 {
+		t.Errorf("Clyde's counter is %d, want 1", got)
+	}
+}
@@ -0,0 +1,89 @@
+package sim
+
+import (
+	"time"
+
+	"github.com/vinser/pacmanai/internal/entity"
+	"github.com/vinser/pacmanai/internal/maze"
+)
+
+// EventKind identifies something that happened during a tick.
+type EventKind int
+
+const (
+	DotEaten EventKind = iota
+	PowerPelletEaten
+	GhostEaten
+	FruitEaten
+	ExtraLife
+	LifeLost
+	LevelCleared
+	GameEnded
+)
+
+// Event is a single reward-relevant occurrence within a tick.
+type Event struct {
+	Kind   EventKind
+	Points int
+	Pos    entity.Position
+}
+
+// Observation is a snapshot of the game after a tick.
+// Maze is shared with the game and must be treated as read-only.
+type Observation struct {
+	Tick          int
+	Phase         Phase
+	Level         int
+	Score         int
+	HighScore     int
+	Lives         int
+	RemainingDots int
+	PowerMode     bool
+	// PowerLeft is how much longer frightened ghosts stay blue.
+	PowerLeft time.Duration
+	Maze      *maze.Maze
+	// Fruit is the bonus fruit on the board, or nil.
+	Fruit     *entity.Fruit
+	Pacman    entity.Position
+	PacmanDir entity.Direction
+	Ghosts    []entity.GhostView
+}
+
+// Result is what a single Step produces.
+type Result struct {
+	Observation Observation
+	Events      []Event
+	// Reward is the training reward paid during the tick and Rewards
+	// lists its terms.
+	Reward  float64
+	Rewards []entity.RewardEvent
+	Done    bool
+}
+
+// powerLeft returns the frightened time remaining, zero outside power mode.
+func (g *Game) powerLeft() time.Duration {
+	if !g.powerMode {
+		return 0
+	}
+	return max(g.powerModeUntil.Sub(g.clock.Now()), 0)
+}
+
+// Observe returns a snapshot of the current game state.
+func (g *Game) Observe() Observation {
+	return Observation{
+		Tick:          g.tick,
+		Phase:         g.phase,
+		Level:         g.level.Index,
+		Score:         g.score.Get(),
+		HighScore:     g.score.GetHigh(),
+		Lives:         g.pacman.Lives(),
+		RemainingDots: g.level.RemainingDots,
+		PowerMode:     g.powerMode,
+		PowerLeft:     g.powerLeft(),
+		Fruit:         g.fruit,
+		Maze:          g.level.Maze,
+		Pacman:        g.pacman.Pos(),
+		PacmanDir:     g.pacman.Dir(),
+		Ghosts:        g.ghostViews(),
+	}
+}
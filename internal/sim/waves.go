@@ -0,0 +1,44 @@
+package sim
+
+import (
+	"time"
+
+	"github.com/vinser/pacmanai/internal/entity"
+)
+
+// waveScheduler alternates ghosts between scatter and chase according to a
+// level's wave table. Even waves are scatter, odd waves are chase, and the
+// mode after the last wave lasts for good. A new schedule starts on every
+// level and after every lost life.
+type waveScheduler struct {
+	waves   []time.Duration
+	index   int
+	elapsed time.Duration
+}
+
+func newWaveScheduler(waves []time.Duration) *waveScheduler {
+	return &waveScheduler{waves: waves}
+}
+
+// mode returns the ghost state of the current wave.
+func (w *waveScheduler) mode() entity.GhostState {
+	if w.index%2 == 0 {
+		return entity.Scatter
+	}
+	return entity.Chase
+}
+
+// advance moves the schedule forward by d and reports whether the mode
+// changed.
+func (w *waveScheduler) advance(d time.Duration) bool {
+	before := w.mode()
+	w.elapsed += d
+	for w.index < len(w.waves) && w.elapsed >= w.waves[w.index] {
+		w.elapsed -= w.waves[w.index]
+		w.index++
+	}
+	if w.index == len(w.waves) {
+		w.elapsed = 0
+	}
+	return w.mode() != before
+}
@@ -0,0 +1,116 @@
+package sim
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vinser/pacmanai/internal/clock"
+	"github.com/vinser/pacmanai/internal/entity"
+)
+
+func TestWaveScheduler(t *testing.T) {
+	s := time.Second
+	w := newWaveScheduler([]time.Duration{7 * s, 20 * s, 5 * s})
+	tests := []struct {
+		advance time.Duration
+		changed bool
+		want    entity.GhostState
+	}{
+		{0, false, entity.Scatter},
+		{7*s - time.Millisecond, false, entity.Scatter},
+		{time.Millisecond, true, entity.Chase},
+		{19 * s, false, entity.Chase},
+		{s, true, entity.Scatter},
+		// One long step passes the rest of the last scatter at once.
+		{time.Hour, true, entity.Chase},
+		{time.Hour, false, entity.Chase},
+	}
+	for i, tt := range tests {
+		changed := w.advance(tt.advance)
+		if changed != tt.changed || w.mode() != tt.want {
+			t.Errorf("step %d (+%v): mode %v changed=%v, want %v changed=%v",
+				i, tt.advance, w.mode(), changed, tt.want, tt.changed)
+		}
+	}
+
+	// A step spanning a whole wave lands in the one after it and reports no
+	// change, as the mode is the same again.
+	w = newWaveScheduler([]time.Duration{s, s, s})
+	if w.advance(2*s+time.Millisecond) || w.mode() != entity.Scatter {
+		t.Errorf("after two waves in one step: mode %v, want scatter and no change", w.mode())
+	}
+}
+
+// newTickGame starts a game on the tick clock and plays through the intro.
+func newTickGame(t *testing.T) *Game {
+	t.Helper()
+	g := NewGame(Config{Seed: 1, Clock: clock.NewTicks(TickDuration)})
+	g.Step(NoAction)
+	if g.Phase() != PhasePlaying {
+		t.Fatalf("phase %v after the first step, want playing", g.Phase())
+	}
+	return g
+}
+
+func TestWavesPauseDuringFright(t *testing.T) {
+	g := newTickGame(t)
+	g.frighten()
+	before := *g.waves
+	for {
+		g.Step(NoAction)
+		if !g.Observe().PowerMode {
+			break
+		}
+		if g.waves.index != before.index || g.waves.elapsed != before.elapsed {
+			t.Fatalf("waves moved from %+v to %+v while ghosts were frightened", before, *g.waves)
+		}
+	}
+	// The tick that ends the fright already counts.
+	if g.waves.elapsed != before.elapsed+TickDuration {
+		t.Errorf("waves at %v after fright, want %v", g.waves.elapsed, before.elapsed+TickDuration)
+	}
+}
+
+func TestWavesRestart(t *testing.T) {
+	// intoChase steps g until its ghosts chase.
+	intoChase := func(t *testing.T, g *Game) {
+		t.Helper()
+		for g.waves.mode() != entity.Chase {
+			g.Step(NoAction)
+			if g.Phase() != PhasePlaying {
+				t.Fatalf("phase %v before the first chase", g.Phase())
+			}
+		}
+	}
+	// assertRestarted fails unless g is back at the start of the first
+	// scatter wave with its ghosts scattering.
+	assertRestarted := func(t *testing.T, g *Game) {
+		t.Helper()
+		if g.waves.index != 0 || g.waves.elapsed != 0 {
+			t.Errorf("waves at wave %d after %v, want a fresh start", g.waves.index, g.waves.elapsed)
+		}
+		for _, gh := range g.ghosts {
+			if s := gh.State(); s != entity.Scatter {
+				t.Errorf("%s is in %v, want scatter", gh.Type(), s)
+			}
+		}
+	}
+
+	t.Run("death", func(t *testing.T) {
+		g := newTickGame(t)
+		intoChase(t, g)
+		killer := g.ghosts[0]
+		killer.SetPos(g.pacman.Pos())
+		if !g.checkCollisions() || g.Phase() != PhaseRespawning {
+			t.Fatalf("no life lost: phase %v", g.Phase())
+		}
+		assertRestarted(t, g)
+	})
+
+	t.Run("level", func(t *testing.T) {
+		g := newTickGame(t)
+		intoChase(t, g)
+		g.advanceLevel()
+		assertRestarted(t, g)
+	})
+}
@@ -16,6 +16,8 @@ import (
 // State holds persistent game data such as high scores.
 type State struct {
 	HighScore int `json:"high_score"`
+	// LastSeed is the random seed of the most recently played game.
+	LastSeed int64 `json:"last_seed"`
 	// Future fields can be added here
 }
 